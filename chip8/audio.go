@@ -0,0 +1,14 @@
+package chip8
+
+// PatternBeeper is implemented by a Frontend able to play XO-CHIP's
+// 128-bit audio pattern buffer at a programmed pitch, rather than a plain
+// square-wave Beep. Run type-asserts for it on every timer tick, so
+// Frontends that don't implement it keep working via Beep alone.
+type PatternBeeper interface {
+	Frontend
+	// BeepPattern is called with the VM's current 16-byte audio pattern
+	// and pitch register whenever the sound timer is ticked in ModeXOCHIP,
+	// in place of Beep, with on reporting whether the sound timer is
+	// currently non-zero. In other modes Run calls Beep instead.
+	BeepPattern(buf [16]uint8, pitch uint8, on bool)
+}
@@ -0,0 +1,805 @@
+// Package chip8 implements a CHIP-8 virtual machine core.
+//
+// The core is deliberately decoupled from any particular rendering, input
+// or audio backend: it knows nothing about SDL, terminals, or any other
+// windowing toolkit. Callers supply a Frontend implementation and the VM
+// drives it, which keeps the interpreter embeddable, testable in
+// isolation, and reusable in non-graphical contexts.
+//
+// Follows the CHIP-8 technical reference found at http://devernay.free.fr/hacks/chip8/C8TECH10.HTM
+package chip8
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// VM constants
+const (
+	totalMemory       = 0x1000
+	totalMemoryXOCHIP = 0x10000
+	pcStartAddr       = 0x200
+
+	// TimerPeriod is the real-time interval at which the delay and sound
+	// timers are decremented, i.e. 60Hz.
+	TimerPeriod = time.Second / 60
+
+	// DefaultClockSpeed is the number of instructions executed per second
+	// absent a call to SetClockSpeed, matching real CHIP-8 interpreters
+	// closely enough for most ROMs.
+	DefaultClockSpeed = 700
+
+	// defaultPitch is XO-CHIP's neutral Fx3A pitch register value, giving
+	// an audio pattern playback rate of 4000Hz.
+	defaultPitch = 64
+
+	// keyReleasePollInterval throttles the WaitForKeyRelease quirk's wait
+	// loop, matching the poll interval pkg/ansi's WaitKey already uses.
+	keyReleasePollInterval = 10 * time.Millisecond
+
+	// ScreenWidth and ScreenHeight are the low-resolution (original
+	// CHIP-8) display dimensions.
+	ScreenWidth  = 64
+	ScreenHeight = 32
+
+	// HiresWidth and HiresHeight are the SUPER-CHIP/XO-CHIP high
+	// resolution display dimensions.
+	HiresWidth  = 128
+	HiresHeight = 64
+)
+
+// Mode selects which dialect of CHIP-8 the VM behaves as. This governs
+// which extended opcodes are recognised; the CHIP-8/SUPER-CHIP/XO-CHIP
+// behavioural ambiguities (shift semantics, I increment on save/load,
+// etc.) are controlled separately by Quirks.
+type Mode int
+
+const (
+	// ModeCHIP8 restricts the VM to the original CHIP-8 instruction set.
+	ModeCHIP8 Mode = iota
+	// ModeSCHIP additionally recognises the SUPER-CHIP 1.1 extensions:
+	// hi-res display, scrolling, the large font and RPL flag storage.
+	ModeSCHIP
+	// ModeXOCHIP additionally recognises the XO-CHIP extensions: register
+	// range save/load, a 16-bit long LD I, multiple display planes and
+	// the audio pattern buffer.
+	ModeXOCHIP
+)
+
+// Frontend is implemented by anything capable of rendering a CHIP-8
+// display, producing sound, and reading the keypad. The VM is agnostic to
+// how a Frontend is implemented, so it can be driven by an SDL window, a
+// terminal, or a headless test harness.
+type Frontend interface {
+	// Draw renders the current display. pixels has one column per pixel of
+	// the VM's current Width() and one row per pixel of its Height(). Each
+	// value is 0 or 1, except under ModeXOCHIP where it is a 0-3 plane
+	// bitmask (see pixelsSlice).
+	Draw(pixels [][]byte)
+	// Beep turns the VM's tone on or off.
+	Beep(on bool)
+	// PollKeys returns the current keypad state as a 16-bit mask, one bit
+	// per key.
+	PollKeys() uint16
+	// WaitKey blocks until a key is pressed and returns it.
+	WaitKey() uint8
+}
+
+// VM is an emulated CHIP-8 virtual machine.
+type VM struct {
+	mode       Mode   // Which opcode dialect this VM understands
+	quirks     Quirks // Behavioural ambiguities this VM resolves per quirks.go
+	clockSpeed int    // Instructions executed per second, set by SetClockSpeed
+
+	opcode     uint16     // 16-bit opcode of the current instruction
+	regV       [16]uint8  // 16 general purpose 8-bit registers
+	regI       uint16     // 16-bit register that is generally used to store memory addresses
+	delayTimer uint8      // Delay timer
+	soundTimer uint8      // Sound timer
+	pc         uint16     // Program counter
+	sp         uint8      // Stack pointer
+	stack      [16]uint16 // A stack of 16 16-bit values
+	memory     []uint8    // Global memory; 4 KB, or 64 KB in ModeXOCHIP
+
+	rpl         [16]uint8 // SUPER-CHIP RPL user flags, set/read by Fx75/Fx85
+	audioBuffer [16]uint8 // XO-CHIP audio pattern buffer, loaded by F002
+	pitch       uint8     // XO-CHIP audio playback pitch, set by Fx3A
+
+	// A 16-bit integer to hold the current key values in the form of
+	// individual bits. So when 0 is pushed in the keypad, the 0'th bit
+	// will be set and so on.
+	key uint16
+
+	hires bool  // Whether the display is in SUPER-CHIP/XO-CHIP hi-res mode
+	plane uint8 // XO-CHIP active draw/scroll plane bitmask (bit0, bit1)
+
+	// Display planes, each Width() x Height() and row-major. Only plane 0
+	// is used outside ModeXOCHIP.
+	planes [2][]uint8
+
+	clearFlag bool // Clear screen flag
+	drawFlag  bool // Draw sprite flag
+
+	frontend     Frontend  // I/O layer
+	lastDrawTime time.Time // Last time Dxyn ran, used by the DisplayWait quirk
+
+	rng  *rand.Rand // Used by the Cxkk RND opcode
+	seed int64      // Seed rng was created from, recorded for State/Restore
+
+	breakpoints   Breakpoints   // Consulted by AtBreakpoint, see debug.go
+	registerWatch RegisterWatch // Fired by Step, see debug.go
+	memoryWatch   MemoryWatch   // Fired by Step, see debug.go
+	trace         io.Writer     // Written to by Step, see debug.go
+}
+
+// newRNG returns the *rand.Rand a VM seeded with seed should use.
+func newRNG(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+var fontset = []uint8{
+	0xF0, 0x90, 0x90, 0x90, 0xF0, // 0
+	0x20, 0x60, 0x20, 0x20, 0x70, // 1
+	0xF0, 0x10, 0xF0, 0x80, 0xF0, // 2
+	0xF0, 0x10, 0xF0, 0x10, 0xF0, // 3
+	0x90, 0x90, 0xF0, 0x10, 0x10, // 4
+	0xF0, 0x80, 0xF0, 0x10, 0xF0, // 5
+	0xF0, 0x80, 0xF0, 0x90, 0xF0, // 6
+	0xF0, 0x10, 0x20, 0x40, 0x40, // 7
+	0xF0, 0x90, 0xF0, 0x90, 0xF0, // 8
+	0xF0, 0x90, 0xF0, 0x10, 0xF0, // 9
+	0xF0, 0x90, 0xF0, 0x90, 0x90, // A
+	0xE0, 0x90, 0xE0, 0x90, 0xE0, // B
+	0xF0, 0x80, 0x80, 0x80, 0xF0, // C
+	0xE0, 0x90, 0x90, 0x90, 0xE0, // D
+	0xF0, 0x80, 0xF0, 0x80, 0xF0, // E
+	0xF0, 0x80, 0xF0, 0x80, 0x80, // F
+}
+
+// bigFontOffset is where the SUPER-CHIP 10-byte-per-glyph hi-res font is
+// stored in memory, immediately after the 5-byte-per-glyph font. len(fontset)
+// isn't a Go constant expression since fontset is a slice, so this can't be
+// a const.
+var bigFontOffset = len(fontset)
+
+// bigFontset is the SUPER-CHIP large font, 10 bytes per glyph for digits
+// 0-9, used by Fx30.
+var bigFontset = []uint8{
+	0x3C, 0x7E, 0xE7, 0xC3, 0xC3, 0xC3, 0xC3, 0xE7, 0x7E, 0x3C, // 0
+	0x18, 0x38, 0x58, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x3C, // 1
+	0x3E, 0x7F, 0xC3, 0x06, 0x0C, 0x18, 0x30, 0x60, 0xFF, 0xFF, // 2
+	0x3C, 0x7E, 0xC3, 0x03, 0x0E, 0x0E, 0x03, 0xC3, 0x7E, 0x3C, // 3
+	0x06, 0x0E, 0x1E, 0x36, 0x66, 0xC6, 0xFF, 0xFF, 0x06, 0x06, // 4
+	0xFF, 0xFF, 0xC0, 0xC0, 0xFC, 0xFE, 0x03, 0xC3, 0x7E, 0x3C, // 5
+	0x3E, 0x7C, 0xC0, 0xC0, 0xFC, 0xFE, 0xC3, 0xC3, 0x7E, 0x3C, // 6
+	0xFF, 0xFF, 0x03, 0x06, 0x0C, 0x18, 0x30, 0x30, 0x30, 0x30, // 7
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7E, 0x7E, 0xC3, 0xC3, 0x7E, 0x3C, // 8
+	0x3C, 0x7E, 0xC3, 0xC3, 0x7F, 0x3F, 0x03, 0x03, 0x3E, 0x7C, // 9
+}
+
+// ParseMode parses a Mode from its CLI/config name ("chip8", "schip" or
+// "xochip", case-insensitively).
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(name) {
+	case "chip8":
+		return ModeCHIP8, nil
+	case "schip":
+		return ModeSCHIP, nil
+	case "xochip":
+		return ModeXOCHIP, nil
+	default:
+		return 0, fmt.Errorf("chip8: unknown mode %q", name)
+	}
+}
+
+// NewVM creates a new instance of an emulated CHIP-8 VM driven by the
+// given Frontend, in the given Mode. Its RNG is seeded from the current
+// time; use NewVMSeeded for a reproducible seed.
+func NewVM(frontend Frontend, mode Mode) (*VM, error) {
+	return NewVMSeeded(frontend, mode, time.Now().UnixNano())
+}
+
+// NewVMSeeded creates a new VM exactly like NewVM, but with its RNG seeded
+// from seed rather than the current time. Recording a ROM's input
+// alongside the seed it ran with is enough to reproduce a run exactly,
+// which NewVM's time-based seed can't offer.
+func NewVMSeeded(frontend Frontend, mode Mode, seed int64) (*VM, error) {
+	memSize := totalMemory
+	if mode == ModeXOCHIP {
+		memSize = totalMemoryXOCHIP
+	}
+	vm := &VM{
+		mode:       mode,
+		quirks:     defaultQuirksForMode(mode),
+		clockSpeed: DefaultClockSpeed,
+		pc:         pcStartAddr,
+		memory:     make([]uint8, memSize),
+		plane:      0x1,
+		pitch:      defaultPitch,
+		frontend:   frontend,
+		seed:       seed,
+		rng:        newRNG(seed),
+	}
+	vm.allocPlanes()
+	if copy(vm.memory, fontset) != len(fontset) {
+		return nil, errors.New("chip8: error copying fontset data to memory")
+	}
+	if mode != ModeCHIP8 {
+		if copy(vm.memory[bigFontOffset:], bigFontset) != len(bigFontset) {
+			return nil, errors.New("chip8: error copying big fontset data to memory")
+		}
+	}
+	return vm, nil
+}
+
+// LoadProgram loads a given CHIP-8 program into the VM's memory
+func (vm *VM) LoadProgram(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("chip8: error loading program: %v", err)
+	}
+	size := len(data)
+	if size > len(vm.memory)-pcStartAddr {
+		return errors.New("chip8: program size exceeds the maximum size")
+	}
+	if copy(vm.memory[pcStartAddr:], data) != size {
+		return errors.New("chip8: error copying program data into VM's memory")
+	}
+	return nil
+}
+
+// Width returns the current display width, which depends on whether the
+// VM is in SUPER-CHIP/XO-CHIP hi-res mode.
+func (vm *VM) Width() int {
+	if vm.hires {
+		return HiresWidth
+	}
+	return ScreenWidth
+}
+
+// Height returns the current display height, which depends on whether the
+// VM is in SUPER-CHIP/XO-CHIP hi-res mode.
+func (vm *VM) Height() int {
+	if vm.hires {
+		return HiresHeight
+	}
+	return ScreenHeight
+}
+
+// SetClockSpeed sets the number of instructions the VM executes per
+// second. It must be called before Run, since Run paces itself against
+// the clock speed in effect when it starts.
+func (vm *VM) SetClockSpeed(ips int) {
+	vm.clockSpeed = ips
+}
+
+// Run drives the VM, reading and executing instructions and servicing the
+// Frontend, until the program raises an error or ctx is cancelled. Instructions
+// are paced at vm.clockSpeed per second, while the delay and sound timers tick
+// independently at 60Hz, so the two no longer drift together under a fast or
+// slow clock speed.
+func (vm *VM) Run(ctx context.Context) error {
+	cycle := time.NewTicker(time.Second / time.Duration(vm.clockSpeed))
+	defer cycle.Stop()
+	timer := time.NewTicker(TimerPeriod)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if vm.delayTimer > 0 {
+				vm.delayTimer--
+			}
+			if vm.soundTimer > 0 {
+				vm.soundTimer--
+			}
+			if pb, ok := vm.frontend.(PatternBeeper); ok && vm.mode == ModeXOCHIP {
+				pb.BeepPattern(vm.audioBuffer, vm.pitch, vm.soundTimer > 0)
+			} else {
+				vm.frontend.Beep(vm.soundTimer > 0)
+			}
+		case <-cycle.C:
+			if err := vm.readNextInstruction(); err != nil {
+				return err
+			}
+
+			if vm.clearFlag {
+				vm.nullifyPixels()
+				vm.clearFlag = false
+			}
+			if vm.drawFlag {
+				vm.frontend.Draw(vm.pixelsSlice())
+				vm.drawFlag = false
+			}
+
+			vm.key = vm.frontend.PollKeys()
+		}
+	}
+}
+
+// registerRange returns the register indices from x to y inclusive, used by
+// the XO-CHIP 5xy2/5xy3 opcodes. Per the XO-CHIP spec the range is walked in
+// whichever direction x and y imply, not sorted into ascending order: x > y
+// means the range (and its [I] layout) runs in descending register order.
+func registerRange(x, y uint8) []uint8 {
+	if x <= y {
+		regs := make([]uint8, 0, int(y-x)+1)
+		for r := x; ; r++ {
+			regs = append(regs, r)
+			if r == y {
+				break
+			}
+		}
+		return regs
+	}
+	regs := make([]uint8, 0, int(x-y)+1)
+	for r := x; ; r-- {
+		regs = append(regs, r)
+		if r == y {
+			break
+		}
+	}
+	return regs
+}
+
+func (vm *VM) unknownOpcode() error {
+	return fmt.Errorf("chip8: unknown opcode: %04X", vm.opcode)
+}
+
+// allocPlanes (re)allocates the display planes to match the current
+// resolution, clearing them in the process.
+func (vm *VM) allocPlanes() {
+	size := vm.Width() * vm.Height()
+	for p := range vm.planes {
+		vm.planes[p] = make([]uint8, size)
+	}
+}
+
+// activePlanes returns the indices of the display planes that drawing and
+// scrolling operations currently apply to. Outside ModeXOCHIP there is
+// only ever one, always-active plane.
+func (vm *VM) activePlanes() []int {
+	if vm.mode != ModeXOCHIP {
+		return []int{0}
+	}
+	var planes []int
+	if vm.plane&0x1 != 0 {
+		planes = append(planes, 0)
+	}
+	if vm.plane&0x2 != 0 {
+		planes = append(planes, 1)
+	}
+	return planes
+}
+
+func (vm *VM) nullifyPixels() {
+	for _, p := range vm.planes {
+		for i := range p {
+			p[i] = 0
+		}
+	}
+}
+
+// setHires switches the display resolution, reallocating (and clearing)
+// the display planes to match.
+func (vm *VM) setHires(hires bool) {
+	vm.hires = hires
+	vm.allocPlanes()
+}
+
+// pixelsSlice composites the display planes into a slice of slices for
+// handing off to a Frontend. Outside ModeXOCHIP only plane 0 is ever lit,
+// so each value is a plain 0/1 pixel; under ModeXOCHIP a pixel lit on
+// plane 1 alone, plane 2 alone, or both is distinguished by value (1, 2 or
+// 3), so a Frontend that wants to render XO-CHIP's two colours can.
+func (vm *VM) pixelsSlice() [][]byte {
+	w, h := vm.Width(), vm.Height()
+	pixels := make([][]byte, w)
+	for x := 0; x < w; x++ {
+		pixels[x] = make([]byte, h)
+		for y := 0; y < h; y++ {
+			i := y*w + x
+			pixels[x][y] = vm.planes[0][i] | vm.planes[1][i]<<1
+		}
+	}
+	return pixels
+}
+
+// initSprite draws an 8xN (or, for SUPER-CHIP/XO-CHIP when n is 0, 16x16)
+// sprite at (x, y) into every active display plane, XORing it onto the
+// existing contents. Pixels past the screen edges are dropped under the
+// ClipSprites quirk and wrap to the opposite edge otherwise.
+func (vm *VM) initSprite(x uint8, y uint8, n uint8) {
+	w, h := vm.Width(), vm.Height()
+	wide := n == 0
+	rows := int(n)
+	cols := 8
+	if wide {
+		rows = 16
+		cols = 16
+	}
+
+	vm.regV[0xF] = 0
+	for _, p := range vm.activePlanes() {
+		grid := vm.planes[p]
+		for row := 0; row < rows; row++ {
+			var spriteRow uint16
+			if wide {
+				spriteRow = uint16(vm.memory[vm.regI+uint16(row*2)])<<8 | uint16(vm.memory[vm.regI+uint16(row*2+1)])
+			} else {
+				spriteRow = uint16(vm.memory[vm.regI+uint16(row)]) << 8
+			}
+			for col := 0; col < cols; col++ {
+				bit := uint8((spriteRow >> uint(15-col)) & 0x1)
+				if bit == 0 {
+					continue
+				}
+				px, py := int(x)+col, int(y)+row
+				if vm.quirks.ClipSprites {
+					if px >= w || py >= h {
+						continue
+					}
+				} else {
+					px, py = px%w, py%h
+				}
+				i := py*w + px
+				if grid[i] == 1 {
+					vm.regV[0xF] = 1
+				}
+				grid[i] ^= 1
+			}
+		}
+	}
+}
+
+func (vm *VM) readNextInstruction() error {
+	vm.opcode = uint16(vm.memory[vm.pc])<<8 | uint16(vm.memory[vm.pc+1]) // 16-bit instruction opcode
+	x := uint8((vm.opcode >> 8) & 0x000F)                                // the lower 4 bits of the high byte of the instruction
+	y := uint8((vm.opcode >> 4) & 0x000F)                                // the upper 4 bits of the low byte of the instruction
+	n := uint8(vm.opcode & 0x000F)                                       // the lowest 4 bits of the instruction
+	kk := uint8(vm.opcode & 0x00FF)                                      // the lowest 8 bits of the instruction
+	nnn := uint16(vm.opcode & 0x0FFF)                                    // the lowest 12 bits of the instruction
+
+	switch vm.opcode & 0xF000 { // Compare against the first 4 bits of the instruction only
+	case 0x0000:
+		switch {
+		case kk == 0xE0: // CLS
+			vm.clearFlag = true
+			vm.pc += 2
+		case kk == 0xEE: // RET
+			vm.sp--
+			vm.pc = vm.stack[vm.sp] + 2
+		case kk&0xF0 == 0xC0 && vm.mode != ModeCHIP8: // SCD n - scroll display down n pixels
+			vm.scrollDown(int(n))
+			vm.pc += 2
+		case kk&0xF0 == 0xD0 && vm.mode == ModeXOCHIP: // SCU n - scroll display up n pixels
+			vm.scrollUp(int(n))
+			vm.pc += 2
+		case kk == 0xFB && vm.mode != ModeCHIP8: // SCR - scroll display right 4 pixels
+			vm.scrollRight(4)
+			vm.pc += 2
+		case kk == 0xFC && vm.mode != ModeCHIP8: // SCL - scroll display left 4 pixels
+			vm.scrollLeft(4)
+			vm.pc += 2
+		case kk == 0xFE && vm.mode != ModeCHIP8: // LOW - switch to lo-res display
+			vm.setHires(false)
+			vm.pc += 2
+		case kk == 0xFF && vm.mode != ModeCHIP8: // HIGH - switch to hi-res display
+			vm.setHires(true)
+			vm.pc += 2
+		default:
+			return vm.unknownOpcode()
+		}
+		break
+	case 0x1000: // JP nnn
+		vm.pc = nnn
+		break
+	case 0x2000: // CALL nnn
+		vm.stack[vm.sp] = vm.pc
+		vm.sp++
+		vm.pc = nnn
+		break
+	case 0x3000: // SE Vx, kk
+		if vm.regV[x] == kk {
+			vm.pc += 2
+		}
+		vm.pc += 2
+		break
+	case 0x4000: // SNE Vx, kk
+		if vm.regV[x] != kk {
+			vm.pc += 2
+		}
+		vm.pc += 2
+		break
+	case 0x5000:
+		switch n {
+		case 0x0: // SE Vx, Vy
+			if vm.regV[x] == vm.regV[y] {
+				vm.pc += 2
+			}
+			vm.pc += 2
+			break
+		case 0x2: // XO-CHIP: save Vx..Vy to [I], without changing I
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			for i, reg := range registerRange(x, y) {
+				vm.memory[vm.regI+uint16(i)] = vm.regV[reg]
+			}
+			vm.pc += 2
+			break
+		case 0x3: // XO-CHIP: load Vx..Vy from [I], without changing I
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			for i, reg := range registerRange(x, y) {
+				vm.regV[reg] = vm.memory[vm.regI+uint16(i)]
+			}
+			vm.pc += 2
+			break
+		default:
+			return vm.unknownOpcode()
+		}
+		break
+	case 0x6000: // LD Vx, kk
+		vm.regV[x] = kk
+		vm.pc += 2
+		break
+	case 0x7000: // ADD Vx, kk
+		vm.regV[x] += kk
+		vm.pc += 2
+		break
+	case 0x8000:
+		switch n {
+		case 0x0: // LD Vx, Vy
+			vm.regV[x] = vm.regV[y]
+			break
+		case 0x1: // OR Vx, Vy
+			vm.regV[x] |= vm.regV[y]
+			if vm.quirks.LogicResetVF {
+				vm.regV[0xF] = 0
+			}
+			break
+		case 0x2: // AND Vx, Vy
+			vm.regV[x] &= vm.regV[y]
+			if vm.quirks.LogicResetVF {
+				vm.regV[0xF] = 0
+			}
+			break
+		case 0x3: // XOR Vx, Vy
+			vm.regV[x] ^= vm.regV[y]
+			if vm.quirks.LogicResetVF {
+				vm.regV[0xF] = 0
+			}
+			break
+		case 0x4: // ADD Vx, Vy
+			temp := uint16(vm.regV[x]) + uint16(vm.regV[y])
+			if temp > 255 {
+				vm.regV[0xF] = 1
+			} else {
+				vm.regV[0xF] = 0
+			}
+			vm.regV[x] = uint8(temp & 0x0000FFFF)
+			break
+		case 0x5: // SUB Vx, Vy
+			if vm.regV[x] > vm.regV[y] {
+				vm.regV[0xF] = 1
+			} else {
+				vm.regV[0xF] = 0
+			}
+			vm.regV[x] -= vm.regV[y]
+			break
+		case 0x6: // SHR Vx {, Vy}
+			src := vm.regV[x]
+			if vm.quirks.ShiftUsesVy {
+				src = vm.regV[y]
+			}
+			if src&0x01 == 1 {
+				vm.regV[0xF] = 1
+			} else {
+				vm.regV[0xF] = 0
+			}
+			vm.regV[x] = src / 2
+			break
+		case 0x7: // SUBN Vx, Vy
+			if vm.regV[y] > vm.regV[x] {
+				vm.regV[0xF] = 1
+			} else {
+				vm.regV[0xF] = 0
+			}
+			vm.regV[x] = vm.regV[y] - vm.regV[x]
+			break
+		case 0xE: // SHL Vx {, Vy}
+			src := vm.regV[x]
+			if vm.quirks.ShiftUsesVy {
+				src = vm.regV[y]
+			}
+			if src&0x80 == 0x80 {
+				vm.regV[0xF] = 1
+			} else {
+				vm.regV[0xF] = 0
+			}
+			vm.regV[x] = src * 2
+			break
+		default:
+			return vm.unknownOpcode()
+		}
+		vm.pc += 2
+		break
+	case 0x9000:
+		switch n {
+		case 0x0: // SNE Vx, Vy
+			if vm.regV[x] != vm.regV[y] {
+				vm.pc += 2
+			}
+			vm.pc += 2
+			break
+		default:
+			return vm.unknownOpcode()
+		}
+		break
+	case 0xA000: // LD I, nnn
+		vm.regI = nnn
+		vm.pc += 2
+		break
+	case 0xB000: // JP V0, nnn (or JP Vx, nnn under the JumpQuirk)
+		reg := uint8(0)
+		if vm.quirks.JumpQuirk {
+			reg = x
+		}
+		vm.pc = nnn + uint16(vm.regV[reg])
+		break
+	case 0xC000: // RND Vx, kk
+		vm.regV[x] = uint8(vm.rng.Intn(256)) & kk
+		vm.pc += 2
+		break
+	case 0xD000: // DRW Vx, Vy, n
+		if vm.quirks.DisplayWait {
+			if wait := TimerPeriod - time.Since(vm.lastDrawTime); wait > 0 {
+				time.Sleep(wait)
+			}
+			vm.lastDrawTime = time.Now()
+		}
+		vm.initSprite(vm.regV[x], vm.regV[y], n)
+		vm.pc += 2
+		vm.drawFlag = true
+		break
+	case 0xE000:
+		switch kk {
+		case 0x9E: // SKP Vx
+			if vm.issetKeymask(vm.regV[x]) {
+				vm.pc += 2
+			}
+			break
+		case 0xA1: // SKNP Vx
+			if !vm.issetKeymask(vm.regV[x]) {
+				vm.pc += 2
+			}
+			break
+		default:
+			return vm.unknownOpcode()
+		}
+		vm.pc += 2
+		break
+	case 0xF000:
+		switch kk {
+		case 0x00: // XO-CHIP: LD I, nnnn - long (16-bit) load of I
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			vm.regI = uint16(vm.memory[vm.pc+2])<<8 | uint16(vm.memory[vm.pc+3])
+			vm.pc += 4
+			return nil
+		case 0x01: // XO-CHIP: Fn01 - select drawing/scrolling plane(s)
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			vm.plane = x & 0x3
+			vm.pc += 2
+			break
+		case 0x02: // XO-CHIP: load 16-byte audio pattern buffer from [I]
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			copy(vm.audioBuffer[:], vm.memory[vm.regI:vm.regI+16])
+			vm.pc += 2
+			break
+		case 0x3A: // XO-CHIP: LD PITCH, Vx - set the audio playback pitch
+			if vm.mode != ModeXOCHIP {
+				return vm.unknownOpcode()
+			}
+			vm.pitch = vm.regV[x]
+			break
+		case 0x07: // LD Vx, DT
+			vm.regV[x] = vm.delayTimer
+			break
+		case 0x0A: // LD Vx, K
+			key := vm.frontend.WaitKey()
+			if vm.quirks.WaitForKeyRelease {
+				for vm.issetKeymask(key) {
+					time.Sleep(keyReleasePollInterval)
+					vm.key = vm.frontend.PollKeys()
+				}
+			}
+			vm.regV[x] = key
+			break
+		case 0x15: // LD DT, Vx
+			vm.delayTimer = vm.regV[x]
+			break
+		case 0x18: // LD ST, Vx
+			vm.soundTimer = vm.regV[x]
+			break
+		case 0x1E: // ADD I, Vx
+			vm.regI += uint16(vm.regV[x])
+			break
+		case 0x29: // LD F, Vx
+			vm.regI = uint16(5 * vm.regV[x])
+			break
+		case 0x30: // SUPER-CHIP: LD HF, Vx - point I at the 10-byte hi-res digit
+			if vm.mode == ModeCHIP8 {
+				return vm.unknownOpcode()
+			}
+			vm.regI = uint16(bigFontOffset) + uint16(10*vm.regV[x])
+			break
+		case 0x33: // LD B, Vx
+			vm.memory[vm.regI] = (vm.regV[x] / 100) % 10
+			vm.memory[vm.regI+1] = (vm.regV[x] / 10) % 10
+			vm.memory[vm.regI+2] = vm.regV[x] % 10
+			break
+		case 0x55: // LD [I], Vx
+			for i := uint16(0); i <= uint16(x); i++ {
+				vm.memory[vm.regI+i] = vm.regV[i]
+			}
+			if vm.quirks.LoadStoreIncrementsI {
+				vm.regI += uint16(x) + 1
+			}
+			break
+		case 0x65: // LD Vx, [I]
+			for i := uint16(0); i <= uint16(x); i++ {
+				vm.regV[i] = vm.memory[vm.regI+i]
+			}
+			if vm.quirks.LoadStoreIncrementsI {
+				vm.regI += uint16(x) + 1
+			}
+			break
+		case 0x75: // SUPER-CHIP: LD R, Vx - save V0..Vx to RPL user flags
+			if vm.mode == ModeCHIP8 {
+				return vm.unknownOpcode()
+			}
+			copy(vm.rpl[:x+1], vm.regV[:x+1])
+			break
+		case 0x85: // SUPER-CHIP: LD Vx, R - load V0..Vx from RPL user flags
+			if vm.mode == ModeCHIP8 {
+				return vm.unknownOpcode()
+			}
+			copy(vm.regV[:x+1], vm.rpl[:x+1])
+			break
+		default:
+			return vm.unknownOpcode()
+		}
+		vm.pc += 2
+		break
+	default:
+		return vm.unknownOpcode()
+	}
+	return nil
+}
+
+// RPLFlags returns the current value of the SUPER-CHIP RPL user flags, as
+// set by Fx75.
+func (vm *VM) RPLFlags() [16]uint8 {
+	return vm.rpl
+}
+
+func (vm *VM) issetKeymask(code uint8) bool {
+	mask := uint16(1) << code
+	return vm.key&mask == mask
+}
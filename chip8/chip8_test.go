@@ -0,0 +1,153 @@
+package chip8_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/chip8/chip8test"
+)
+
+// loadProgram writes rom to a temp file and loads it into vm, since
+// chip8.VM.LoadProgram only reads from a path.
+func loadProgram(t *testing.T, vm *chip8.VM, rom []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rom.ch8")
+	if err := os.WriteFile(path, rom, 0644); err != nil {
+		t.Fatalf("writing rom: %v", err)
+	}
+	if err := vm.LoadProgram(path); err != nil {
+		t.Fatalf("LoadProgram: %v", err)
+	}
+}
+
+func TestArithmeticOpcodes(t *testing.T) {
+	rom := []byte{
+		0x60, 0x05, // LD V0, 0x05
+		0x61, 0x03, // LD V1, 0x03
+		0x80, 0x14, // ADD V0, V1
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < len(rom)/2; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if got := vm.V(0); got != 8 {
+		t.Errorf("V0 = %d, want 8", got)
+	}
+	if got := vm.V(0xF); got != 0 {
+		t.Errorf("VF = %d, want 0 (no carry)", got)
+	}
+}
+
+func TestSeededRNGIsDeterministic(t *testing.T) {
+	rom := []byte{
+		0xC0, 0xFF, // RND V0, 0xFF
+		0xC1, 0xFF, // RND V1, 0xFF
+	}
+
+	run := func(seed int64) [2]uint8 {
+		frontend := chip8test.NewFrontend()
+		vm, err := chip8.NewVMSeeded(frontend, chip8.ModeCHIP8, seed)
+		if err != nil {
+			t.Fatalf("NewVMSeeded: %v", err)
+		}
+		loadProgram(t, vm, rom)
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+		return [2]uint8{vm.V(0), vm.V(1)}
+	}
+
+	const seed = 42
+	first := run(seed)
+	second := run(seed)
+	if first != second {
+		t.Errorf("two VMs seeded with %d produced different RND output: %v != %v", seed, first, second)
+	}
+}
+
+// TestXOCHIPRegisterRangeSaveOrder covers both directions of the XO-CHIP
+// 5xy2 "save Vx..Vy to [I]" opcode: the saved order must follow x and y's
+// relative order, not be sorted ascending.
+func TestXOCHIPRegisterRangeSaveOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		opcode []byte
+		want   []uint8
+	}{
+		{"ascending V0..V2", []byte{0x50, 0x22}, []uint8{1, 2, 3}},
+		{"descending V2..V0", []byte{0x52, 0x02}, []uint8{3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := []byte{
+				0x60, 0x01, // LD V0, 1
+				0x61, 0x02, // LD V1, 2
+				0x62, 0x03, // LD V2, 3
+				0xA3, 0x00, // LD I, 0x300
+			}
+			rom = append(rom, tt.opcode...)
+
+			frontend := chip8test.NewFrontend()
+			vm, err := chip8.NewVM(frontend, chip8.ModeXOCHIP)
+			if err != nil {
+				t.Fatalf("NewVM: %v", err)
+			}
+			loadProgram(t, vm, rom)
+
+			for i := 0; i < len(rom)/2; i++ {
+				if err := vm.Step(); err != nil {
+					t.Fatalf("Step: %v", err)
+				}
+			}
+
+			mem := vm.Memory()
+			got := mem[0x300 : 0x300+len(tt.want)]
+			for i, want := range tt.want {
+				if got[i] != want {
+					t.Errorf("memory[%#x] = %d, want %d (got %v)", 0x300+i, got[i], want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestSnapshotRestoreMode covers restoring a State into a VM constructed
+// with a different mode/quirks than the one that produced the snapshot: the
+// restored VM must pick up the snapshot's mode and quirks, not keep its own.
+func TestSnapshotRestoreMode(t *testing.T) {
+	source, err := chip8.NewVM(chip8test.NewFrontend(), chip8.ModeXOCHIP)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	source.SetQuirks(chip8.Profiles["XO_CHIP"])
+	snapshot := source.Snapshot()
+
+	vm, err := chip8.NewVM(chip8test.NewFrontend(), chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	vm.Restore(snapshot)
+
+	rom := []byte{0xF0, 0x01} // XO-CHIP: Fn01 - select drawing/scrolling plane(s)
+	loadProgram(t, vm, rom)
+	if err := vm.Step(); err != nil {
+		t.Errorf("restored VM rejected an XO-CHIP opcode as unknown, mode wasn't restored: %v", err)
+	}
+	if got := vm.Quirks(); got != chip8.Profiles["XO_CHIP"] {
+		t.Errorf("Quirks() = %+v, want %+v", got, chip8.Profiles["XO_CHIP"])
+	}
+}
@@ -0,0 +1,99 @@
+// Package chip8test provides a headless chip8.Frontend for driving a VM
+// without any real display, audio or input device. It is intended for
+// unit tests and other non-graphical contexts that want to run a ROM and
+// inspect the resulting state.
+package chip8test
+
+import "sync"
+
+// Frontend is a headless chip8.Frontend. Draw calls are recorded so a
+// test can assert on the rendered frames, keys can be queued up front via
+// QueueKey, and Beep simply records whether the tone is on.
+type Frontend struct {
+	mu sync.Mutex
+
+	frames  [][][]byte
+	beeping bool
+	keys    uint16
+	queue   []uint8
+}
+
+// NewFrontend returns a new headless Frontend.
+func NewFrontend() *Frontend {
+	return &Frontend{}
+}
+
+// Draw records the given frame so it can be inspected later via Frames.
+func (f *Frontend) Draw(pixels [][]byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	frame := make([][]byte, len(pixels))
+	for i, col := range pixels {
+		frame[i] = append([]byte(nil), col...)
+	}
+	f.frames = append(f.frames, frame)
+}
+
+// Beep records whether the tone is currently on.
+func (f *Frontend) Beep(on bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.beeping = on
+}
+
+// PollKeys returns the key mask most recently set via SetKeys.
+func (f *Frontend) PollKeys() uint16 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.keys
+}
+
+// WaitKey pops the next key queued via QueueKey. If the queue is empty it
+// returns 0 immediately rather than blocking, since there is no user to
+// wait on in a headless context.
+func (f *Frontend) WaitKey() uint8 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.queue) == 0 {
+		return 0
+	}
+	key := f.queue[0]
+	f.queue = f.queue[1:]
+	return key
+}
+
+// SetKeys sets the key mask returned by subsequent calls to PollKeys.
+func (f *Frontend) SetKeys(mask uint16) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = mask
+}
+
+// QueueKey appends a key to be returned by a future call to WaitKey.
+func (f *Frontend) QueueKey(key uint8) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = append(f.queue, key)
+}
+
+// Frames returns every frame recorded by Draw so far.
+func (f *Frontend) Frames() [][][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([][][]byte(nil), f.frames...)
+}
+
+// Beeping reports whether the most recent Beep call turned the tone on.
+func (f *Frontend) Beeping() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.beeping
+}
@@ -0,0 +1,128 @@
+package chip8
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// RegisterWatch is called by Step when the given register's value changes.
+type RegisterWatch func(reg uint8, old, new uint8)
+
+// MemoryWatch is called by Step when the byte at the given address
+// changes.
+type MemoryWatch func(addr uint16, old, new uint8)
+
+// Breakpoints are the program-counter addresses a debug front-end driving
+// Step should pause execution at, per AtBreakpoint. Only AtBreakpoint and
+// Continue consult this map; Run and Step never do, so it imposes no cost
+// on the normal Run loop.
+type Breakpoints map[uint16]bool
+
+// AtBreakpoint reports whether the VM is currently sitting at one of vm's
+// Breakpoints.
+func (vm *VM) AtBreakpoint() bool {
+	return vm.breakpoints != nil && vm.breakpoints[vm.pc]
+}
+
+// SetBreakpoints replaces the VM's Breakpoints.
+func (vm *VM) SetBreakpoints(b Breakpoints) {
+	vm.breakpoints = b
+}
+
+// AddBreakpoint adds a single program-counter address to the VM's
+// Breakpoints, creating the map if necessary.
+func (vm *VM) AddBreakpoint(pc uint16) {
+	if vm.breakpoints == nil {
+		vm.breakpoints = Breakpoints{}
+	}
+	vm.breakpoints[pc] = true
+}
+
+// Continue repeatedly calls Step until ctx is cancelled, an instruction
+// returns an error, or the VM reaches a breakpoint it wasn't already
+// sitting at when Continue was called.
+func (vm *VM) Continue(ctx context.Context) error {
+	start := vm.pc
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		if vm.pc != start && vm.AtBreakpoint() {
+			return nil
+		}
+		if err := vm.Step(); err != nil {
+			return err
+		}
+	}
+}
+
+// WatchRegister installs a hook that Step calls whenever it changes a
+// general purpose register's value. Pass nil to remove it.
+func (vm *VM) WatchRegister(fn RegisterWatch) {
+	vm.registerWatch = fn
+}
+
+// WatchMemory installs a hook that Step calls whenever it changes a byte
+// of memory. Pass nil to remove it.
+func (vm *VM) WatchMemory(fn MemoryWatch) {
+	vm.memoryWatch = fn
+}
+
+// Trace makes Step log the opcode, PC and register file to w after every
+// instruction. Pass nil to stop tracing.
+func (vm *VM) Trace(w io.Writer) {
+	vm.trace = w
+}
+
+// Step executes exactly one instruction, servicing the Frontend exactly as
+// Run would for that instruction, then reports any change to watched
+// registers or memory and, if tracing is enabled, logs the step. It does
+// not consult Breakpoints or tick the timers — callers driving a debug
+// loop are expected to call AtBreakpoint between steps and tick timers
+// themselves if they want that behaviour.
+func (vm *VM) Step() error {
+	pc := vm.pc
+	regBefore := vm.regV
+	var memBefore []uint8
+	if vm.memoryWatch != nil {
+		memBefore = make([]uint8, len(vm.memory))
+		copy(memBefore, vm.memory)
+	}
+
+	if err := vm.readNextInstruction(); err != nil {
+		return err
+	}
+
+	if vm.clearFlag {
+		vm.nullifyPixels()
+		vm.clearFlag = false
+	}
+	if vm.drawFlag {
+		vm.frontend.Draw(vm.pixelsSlice())
+		vm.drawFlag = false
+	}
+	vm.key = vm.frontend.PollKeys()
+
+	if vm.registerWatch != nil {
+		for i, v := range vm.regV {
+			if v != regBefore[i] {
+				vm.registerWatch(uint8(i), regBefore[i], v)
+			}
+		}
+	}
+	if vm.memoryWatch != nil {
+		for addr, v := range vm.memory {
+			if v != memBefore[addr] {
+				vm.memoryWatch(uint16(addr), memBefore[addr], v)
+			}
+		}
+	}
+	if vm.trace != nil {
+		fmt.Fprintf(vm.trace, "%04X: %04X  V=%02X I=%04X SP=%d DT=%d ST=%d\n",
+			pc, vm.opcode, vm.regV, vm.regI, vm.sp, vm.delayTimer, vm.soundTimer)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package debug provides a CHIP-8/SUPER-CHIP/XO-CHIP disassembler, built
+// for ROM authors and debugger front-ends driving chip8.VM's Step API.
+package debug
+
+import "fmt"
+
+// Instruction is one decoded opcode, as found by Disassemble.
+type Instruction struct {
+	Addr     uint16
+	Opcode   uint16
+	Mnemonic string
+}
+
+// Disassemble decodes every opcode in rom, starting at address 0x200
+// (where chip8.VM loads programs), returning one Instruction per opcode in
+// address order. It recognises the full CHIP-8/SUPER-CHIP/XO-CHIP opcode
+// set; mnemonics for dialect-specific opcodes are produced regardless of
+// which dialect the ROM actually targets, mirroring the switch in
+// chip8.VM's instruction decoder. Most opcodes are 2 bytes, but XO-CHIP's
+// F000 long LD I is 4, so Disassemble advances by however many bytes
+// mnemonic reports consuming rather than a fixed stride.
+func Disassemble(rom []byte) []Instruction {
+	const loadAddr = 0x200
+	instructions := make([]Instruction, 0, len(rom)/2)
+	for i := 0; i+1 < len(rom); {
+		opcode := uint16(rom[i])<<8 | uint16(rom[i+1])
+		size := 2
+		text := mnemonic(opcode)
+		if opcode&0xF000 == 0xF000 && opcode&0x00FF == 0x00 && i+3 < len(rom) {
+			nnnn := uint16(rom[i+2])<<8 | uint16(rom[i+3])
+			text = fmt.Sprintf("LD I, %#04x", nnnn)
+			size = 4
+		}
+		instructions = append(instructions, Instruction{
+			Addr:     uint16(loadAddr + i),
+			Opcode:   opcode,
+			Mnemonic: text,
+		})
+		i += size
+	}
+	return instructions
+}
+
+func mnemonic(opcode uint16) string {
+	x := (opcode >> 8) & 0x000F
+	y := (opcode >> 4) & 0x000F
+	n := opcode & 0x000F
+	kk := opcode & 0x00FF
+	nnn := opcode & 0x0FFF
+
+	switch opcode & 0xF000 {
+	case 0x0000:
+		switch {
+		case kk == 0xE0:
+			return "CLS"
+		case kk == 0xEE:
+			return "RET"
+		case kk&0xF0 == 0xC0:
+			return fmt.Sprintf("SCD %d", n)
+		case kk&0xF0 == 0xD0:
+			return fmt.Sprintf("SCU %d", n)
+		case kk == 0xFB:
+			return "SCR"
+		case kk == 0xFC:
+			return "SCL"
+		case kk == 0xFE:
+			return "LOW"
+		case kk == 0xFF:
+			return "HIGH"
+		default:
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+	case 0x1000:
+		return fmt.Sprintf("JP %#03x", nnn)
+	case 0x2000:
+		return fmt.Sprintf("CALL %#03x", nnn)
+	case 0x3000:
+		return fmt.Sprintf("SE V%X, %#02x", x, kk)
+	case 0x4000:
+		return fmt.Sprintf("SNE V%X, %#02x", x, kk)
+	case 0x5000:
+		switch n {
+		case 0x0:
+			return fmt.Sprintf("SE V%X, V%X", x, y)
+		case 0x2:
+			return fmt.Sprintf("LD [I], V%X..V%X", x, y)
+		case 0x3:
+			return fmt.Sprintf("LD V%X..V%X, [I]", x, y)
+		default:
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+	case 0x6000:
+		return fmt.Sprintf("LD V%X, %#02x", x, kk)
+	case 0x7000:
+		return fmt.Sprintf("ADD V%X, %#02x", x, kk)
+	case 0x8000:
+		switch n {
+		case 0x0:
+			return fmt.Sprintf("LD V%X, V%X", x, y)
+		case 0x1:
+			return fmt.Sprintf("OR V%X, V%X", x, y)
+		case 0x2:
+			return fmt.Sprintf("AND V%X, V%X", x, y)
+		case 0x3:
+			return fmt.Sprintf("XOR V%X, V%X", x, y)
+		case 0x4:
+			return fmt.Sprintf("ADD V%X, V%X", x, y)
+		case 0x5:
+			return fmt.Sprintf("SUB V%X, V%X", x, y)
+		case 0x6:
+			return fmt.Sprintf("SHR V%X {, V%X}", x, y)
+		case 0x7:
+			return fmt.Sprintf("SUBN V%X, V%X", x, y)
+		case 0xE:
+			return fmt.Sprintf("SHL V%X {, V%X}", x, y)
+		default:
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+	case 0x9000:
+		if n != 0x0 {
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+		return fmt.Sprintf("SNE V%X, V%X", x, y)
+	case 0xA000:
+		return fmt.Sprintf("LD I, %#03x", nnn)
+	case 0xB000:
+		return fmt.Sprintf("JP V0, %#03x", nnn)
+	case 0xC000:
+		return fmt.Sprintf("RND V%X, %#02x", x, kk)
+	case 0xD000:
+		return fmt.Sprintf("DRW V%X, V%X, %d", x, y, n)
+	case 0xE000:
+		switch kk {
+		case 0x9E:
+			return fmt.Sprintf("SKP V%X", x)
+		case 0xA1:
+			return fmt.Sprintf("SKNP V%X", x)
+		default:
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+	case 0xF000:
+		switch kk {
+		case 0x00:
+			return "LD I, nnnn"
+		case 0x01:
+			return fmt.Sprintf("PLANE %d", x)
+		case 0x02:
+			return "LD AUDIO, [I]"
+		case 0x3A:
+			return fmt.Sprintf("LD PITCH, V%X", x)
+		case 0x07:
+			return fmt.Sprintf("LD V%X, DT", x)
+		case 0x0A:
+			return fmt.Sprintf("LD V%X, K", x)
+		case 0x15:
+			return fmt.Sprintf("LD DT, V%X", x)
+		case 0x18:
+			return fmt.Sprintf("LD ST, V%X", x)
+		case 0x1E:
+			return fmt.Sprintf("ADD I, V%X", x)
+		case 0x29:
+			return fmt.Sprintf("LD F, V%X", x)
+		case 0x30:
+			return fmt.Sprintf("LD HF, V%X", x)
+		case 0x33:
+			return fmt.Sprintf("LD B, V%X", x)
+		case 0x55:
+			return fmt.Sprintf("LD [I], V%X", x)
+		case 0x65:
+			return fmt.Sprintf("LD V%X, [I]", x)
+		case 0x75:
+			return fmt.Sprintf("LD R, V%X", x)
+		case 0x85:
+			return fmt.Sprintf("LD V%X, R", x)
+		default:
+			return fmt.Sprintf("DW %#04x", opcode)
+		}
+	default:
+		return fmt.Sprintf("DW %#04x", opcode)
+	}
+}
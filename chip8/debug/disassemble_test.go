@@ -0,0 +1,67 @@
+package debug_test
+
+import (
+	"testing"
+
+	"github.com/mnafees/chopper/chip8/debug"
+)
+
+func TestDisassemble(t *testing.T) {
+	rom := []byte{
+		0x60, 0x05, // LD V0, 0x05
+		0xF0, 0x00, 0x02, 0x00, // XO-CHIP long LD I, 0x0200 (4 bytes)
+		0x00, 0xE0, // CLS
+	}
+
+	instructions := debug.Disassemble(rom)
+
+	want := []debug.Instruction{
+		{Addr: 0x200, Opcode: 0x6005, Mnemonic: "LD V0, 0x05"},
+		{Addr: 0x202, Opcode: 0xF000, Mnemonic: "LD I, 0x0200"},
+		{Addr: 0x206, Opcode: 0x00E0, Mnemonic: "CLS"},
+	}
+
+	if len(instructions) != len(want) {
+		t.Fatalf("Disassemble returned %d instructions, want %d: %+v", len(instructions), len(want), instructions)
+	}
+	for i, w := range want {
+		if instructions[i] != w {
+			t.Errorf("instruction %d = %+v, want %+v", i, instructions[i], w)
+		}
+	}
+}
+
+func TestDisassembleMnemonics(t *testing.T) {
+	tests := []struct {
+		name   string
+		opcode []byte
+		want   string
+	}{
+		{"CLS", []byte{0x00, 0xE0}, "CLS"},
+		{"RET", []byte{0x00, 0xEE}, "RET"},
+		{"SCR", []byte{0x00, 0xFB}, "SCR"},
+		{"HIGH", []byte{0x00, 0xFF}, "HIGH"},
+		{"JP", []byte{0x12, 0x34}, "JP 0x234"},
+		{"CALL", []byte{0x23, 0x45}, "CALL 0x345"},
+		{"LD Vx, kk", []byte{0x6A, 0x12}, "LD VA, 0x12"},
+		{"5xy2 save range", []byte{0x52, 0x02}, "LD [I], V2..V0"},
+		{"5xy3 load range", []byte{0x50, 0x23}, "LD V0..V2, [I]"},
+		{"SHR", []byte{0x80, 0x16}, "SHR V0 {, V1}"},
+		{"DRW", []byte{0xD0, 0x11}, "DRW V0, V1, 1"},
+		{"SKP", []byte{0xE0, 0x9E}, "SKP V0"},
+		{"LD HF, Vx", []byte{0xF0, 0x30}, "LD HF, V0"},
+		{"LD R, Vx", []byte{0xF1, 0x75}, "LD R, V1"},
+		{"unknown", []byte{0x00, 0x01}, "DW 0x0001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions := debug.Disassemble(tt.opcode)
+			if len(instructions) != 1 {
+				t.Fatalf("Disassemble returned %d instructions, want 1", len(instructions))
+			}
+			if got := instructions[0].Mnemonic; got != tt.want {
+				t.Errorf("Mnemonic = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
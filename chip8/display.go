@@ -0,0 +1,56 @@
+package chip8
+
+// Display scrolling for the SUPER-CHIP/XO-CHIP 00Cn/00Dn/00FB/00FC
+// opcodes. Each operates on every currently active plane.
+
+func (vm *VM) scrollDown(n int) {
+	w, h := vm.Width(), vm.Height()
+	for _, p := range vm.activePlanes() {
+		grid := vm.planes[p]
+		shifted := make([]uint8, len(grid))
+		for row := 0; row < h-n; row++ {
+			copy(shifted[(row+n)*w:(row+n+1)*w], grid[row*w:(row+1)*w])
+		}
+		vm.planes[p] = shifted
+	}
+}
+
+func (vm *VM) scrollUp(n int) {
+	w, h := vm.Width(), vm.Height()
+	for _, p := range vm.activePlanes() {
+		grid := vm.planes[p]
+		shifted := make([]uint8, len(grid))
+		for row := n; row < h; row++ {
+			copy(shifted[(row-n)*w:(row-n+1)*w], grid[row*w:(row+1)*w])
+		}
+		vm.planes[p] = shifted
+	}
+}
+
+func (vm *VM) scrollRight(n int) {
+	w, h := vm.Width(), vm.Height()
+	for _, p := range vm.activePlanes() {
+		grid := vm.planes[p]
+		shifted := make([]uint8, len(grid))
+		for row := 0; row < h; row++ {
+			for col := 0; col < w-n; col++ {
+				shifted[row*w+col+n] = grid[row*w+col]
+			}
+		}
+		vm.planes[p] = shifted
+	}
+}
+
+func (vm *VM) scrollLeft(n int) {
+	w, h := vm.Width(), vm.Height()
+	for _, p := range vm.activePlanes() {
+		grid := vm.planes[p]
+		shifted := make([]uint8, len(grid))
+		for row := 0; row < h; row++ {
+			for col := n; col < w; col++ {
+				shifted[row*w+col-n] = grid[row*w+col]
+			}
+		}
+		vm.planes[p] = shifted
+	}
+}
@@ -0,0 +1,41 @@
+package chip8
+
+// PC returns the VM's current program counter.
+func (vm *VM) PC() uint16 {
+	return vm.pc
+}
+
+// I returns the VM's current value of the I register.
+func (vm *VM) I() uint16 {
+	return vm.regI
+}
+
+// V returns the VM's current value of general purpose register Vi.
+func (vm *VM) V(i uint8) uint8 {
+	return vm.regV[i]
+}
+
+// SP returns the VM's current stack pointer.
+func (vm *VM) SP() uint8 {
+	return vm.sp
+}
+
+// Stack returns the VM's call stack.
+func (vm *VM) Stack() [16]uint16 {
+	return vm.stack
+}
+
+// Memory returns the VM's memory. Callers must not modify it.
+func (vm *VM) Memory() []uint8 {
+	return vm.memory
+}
+
+// DelayTimer returns the VM's current delay timer value.
+func (vm *VM) DelayTimer() uint8 {
+	return vm.delayTimer
+}
+
+// SoundTimer returns the VM's current sound timer value.
+func (vm *VM) SoundTimer() uint8 {
+	return vm.soundTimer
+}
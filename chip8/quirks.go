@@ -0,0 +1,117 @@
+package chip8
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Quirks controls the well-known CHIP-8 behavioural ambiguities that
+// differ between interpreters. ROMs are frequently written against one
+// specific interpreter's quirks, so getting these wrong is a common cause
+// of otherwise-correct ROMs misbehaving.
+type Quirks struct {
+	// ShiftUsesVy makes 8xy6/8xyE shift Vy into Vx, rather than shifting
+	// Vx in place.
+	ShiftUsesVy bool
+	// LoadStoreIncrementsI makes Fx55/Fx65 leave I advanced by x+1
+	// afterwards, rather than unchanged.
+	LoadStoreIncrementsI bool
+	// JumpQuirk makes Bnnn jump to nnn+Vx (x taken from nnn's top nibble)
+	// instead of nnn+V0.
+	JumpQuirk bool
+	// LogicResetVF makes 8xy1/8xy2/8xy3 (OR/AND/XOR) clear VF afterwards.
+	LogicResetVF bool
+	// DisplayWait makes Dxyn block until the next 60Hz tick, as the
+	// original COSMAC VIP did.
+	DisplayWait bool
+	// ClipSprites makes sprites clip at the screen edge instead of
+	// wrapping around to the opposite side.
+	ClipSprites bool
+	// WaitForKeyRelease makes Fx0A wait for the pressed key to be
+	// released before continuing, as the original COSMAC VIP did, rather
+	// than returning as soon as it's pressed.
+	WaitForKeyRelease bool
+}
+
+// Profiles holds the well-known Quirks presets, keyed by the names used by
+// the --quirks flag and by ROM config file lookups.
+var Profiles = map[string]Quirks{
+	"COSMAC_VIP": {
+		ShiftUsesVy:          true,
+		LoadStoreIncrementsI: true,
+		LogicResetVF:         true,
+		DisplayWait:          true,
+		ClipSprites:          true,
+		WaitForKeyRelease:    true,
+	},
+	"SCHIP": {
+		JumpQuirk:   true,
+		ClipSprites: true,
+	},
+	"XO_CHIP": {
+		ClipSprites: true,
+	},
+}
+
+// defaultQuirksForMode returns the Quirks a VM should start with for a
+// given Mode, absent any more specific ROM or CLI override.
+func defaultQuirksForMode(mode Mode) Quirks {
+	switch mode {
+	case ModeSCHIP:
+		return Profiles["SCHIP"]
+	case ModeXOCHIP:
+		return Profiles["XO_CHIP"]
+	default:
+		return Profiles["COSMAC_VIP"]
+	}
+}
+
+// SetQuirks replaces the VM's current Quirks.
+func (vm *VM) SetQuirks(q Quirks) {
+	vm.quirks = q
+}
+
+// Quirks returns the VM's current Quirks.
+func (vm *VM) Quirks() Quirks {
+	return vm.quirks
+}
+
+// ROMHash returns the SHA-1 hash of a ROM's bytes, in the same hex form
+// used by ROMQuirksConfig lookups.
+func ROMHash(rom []byte) string {
+	sum := sha1.Sum(rom)
+	return hex.EncodeToString(sum[:])
+}
+
+// ROMQuirksConfig maps a ROM's SHA-1 hash to the name of a Quirks profile
+// in Profiles, letting users pin specific ROMs to the dialect they were
+// written for.
+type ROMQuirksConfig map[string]string
+
+// LoadROMQuirksConfig reads a ROMQuirksConfig from a JSON file of the form
+// {"<sha1>": "<profile name>", ...}.
+func LoadROMQuirksConfig(path string) (ROMQuirksConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("chip8: error reading quirks config: %v", err)
+	}
+	var cfg ROMQuirksConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("chip8: error parsing quirks config: %v", err)
+	}
+	return cfg, nil
+}
+
+// Lookup returns the Quirks profile configured for the given ROM bytes, if
+// any.
+func (c ROMQuirksConfig) Lookup(rom []byte) (Quirks, bool) {
+	name, ok := c[ROMHash(rom)]
+	if !ok {
+		return Quirks{}, false
+	}
+	q, ok := Profiles[name]
+	return q, ok
+}
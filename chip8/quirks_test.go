@@ -0,0 +1,190 @@
+package chip8_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/chip8/chip8test"
+)
+
+// TestShiftUsesVyQuirk covers 8xy6 (SHR): with ShiftUsesVy, Vx is set to
+// Vy shifted right, not Vx shifted right in place.
+func TestShiftUsesVyQuirk(t *testing.T) {
+	rom := []byte{
+		0x60, 0x05, // LD V0, 5
+		0x61, 0x03, // LD V1, 3
+		0x80, 0x16, // SHR V0 {, V1}
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	vm.SetQuirks(chip8.Quirks{ShiftUsesVy: true})
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < len(rom)/2; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if got := vm.V(0); got != 1 {
+		t.Errorf("V0 = %d, want 1 (3 >> 1, from Vy not Vx)", got)
+	}
+	if got := vm.V(0xF); got != 1 {
+		t.Errorf("VF = %d, want 1 (Vy's low bit)", got)
+	}
+}
+
+// TestJumpQuirk covers Bnnn: with JumpQuirk, the jump adds Vx (x taken
+// from nnn's top nibble) rather than V0.
+func TestJumpQuirk(t *testing.T) {
+	rom := []byte{
+		0x62, 0x10, // LD V2, 0x10
+		0xB2, 0x05, // JP V2, 0x205
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	vm.SetQuirks(chip8.Quirks{JumpQuirk: true})
+	loadProgram(t, vm, rom)
+
+	if err := vm.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if err := vm.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+
+	if got, want := vm.PC(), uint16(0x205+0x10); got != want {
+		t.Errorf("PC = %#x, want %#x (0x205 + V2, not 0x205 + V0)", got, want)
+	}
+}
+
+// TestLogicResetVFQuirk covers 8xy1 (OR): with LogicResetVF, VF is cleared
+// afterwards regardless of its prior value.
+func TestLogicResetVFQuirk(t *testing.T) {
+	rom := []byte{
+		0x60, 0x0F, // LD V0, 0x0F
+		0x61, 0x0F, // LD V1, 0x0F
+		0x6F, 0x01, // LD VF, 1
+		0x80, 0x11, // OR V0, V1
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	vm.SetQuirks(chip8.Quirks{LogicResetVF: true})
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < len(rom)/2; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if got := vm.V(0); got != 0x0F {
+		t.Errorf("V0 = %#x, want 0x0F", got)
+	}
+	if got := vm.V(0xF); got != 0 {
+		t.Errorf("VF = %d, want 0 (reset by the OR, not left at 1)", got)
+	}
+}
+
+// TestClipSpritesQuirk covers Dxyn: with ClipSprites, pixels that fall off
+// the right edge are dropped instead of wrapping to the opposite column.
+func TestClipSpritesQuirk(t *testing.T) {
+	rom := []byte{
+		0x60, 0x3C, // LD V0, 60
+		0x61, 0x00, // LD V1, 0
+		0xA2, 0x08, // LD I, 0x208
+		0xD0, 0x11, // DRW V0, V1, 1
+		0xFF, // sprite data: all 8 columns lit, spanning cols 60-67
+	}
+
+	for _, tt := range []struct {
+		name string
+		clip bool
+	}{
+		{"clipped", true},
+		{"wrapped", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			frontend := chip8test.NewFrontend()
+			vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+			if err != nil {
+				t.Fatalf("NewVM: %v", err)
+			}
+			vm.SetQuirks(chip8.Quirks{ClipSprites: tt.clip})
+			loadProgram(t, vm, rom)
+
+			for i := 0; i < 4; i++ {
+				if err := vm.Step(); err != nil {
+					t.Fatalf("Step: %v", err)
+				}
+			}
+
+			grid := vm.Snapshot().Planes[0]
+			w := chip8.ScreenWidth
+			wrapped := grid[0] == 1
+			if tt.clip && wrapped {
+				t.Errorf("pixel wrapped to column 0 despite ClipSprites")
+			}
+			if !tt.clip && !wrapped {
+				t.Errorf("pixel did not wrap to column 0 without ClipSprites")
+			}
+			if grid[w-4] != 1 {
+				t.Errorf("pixel at column %d = %d, want 1", w-4, grid[w-4])
+			}
+		})
+	}
+}
+
+// TestWaitForKeyReleaseQuirk covers Fx0A: with WaitForKeyRelease, the
+// instruction must block until the pressed key is released rather than
+// returning as soon as it's reported.
+func TestWaitForKeyReleaseQuirk(t *testing.T) {
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	vm.SetQuirks(chip8.Quirks{WaitForKeyRelease: true})
+
+	const key = 5
+	frontend.QueueKey(key)
+	frontend.SetKeys(1 << key)
+
+	state := vm.Snapshot()
+	state.Key = 1 << key
+	vm.Restore(state)
+
+	rom := []byte{0xF5, 0x0A} // LD V5, K
+	loadProgram(t, vm, rom)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		frontend.SetKeys(0)
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- vm.Step() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Step did not return after the key was released; WaitForKeyRelease quirk appears stuck")
+	}
+
+	if got := vm.V(5); got != key {
+		t.Errorf("V5 = %d, want %d", got, key)
+	}
+}
@@ -0,0 +1,105 @@
+package chip8_test
+
+import (
+	"testing"
+
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/chip8/chip8test"
+)
+
+// TestHiresScroll covers the SUPER-CHIP/XO-CHIP hi-res toggle and the
+// 00FB (scroll right) opcode: a sprite drawn at column 0 should end up at
+// column 4 with the vacated columns left unlit.
+func TestHiresScroll(t *testing.T) {
+	rom := []byte{
+		0x00, 0xFF, // HIGH - switch to hi-res
+		0x60, 0x00, // LD V0, 0
+		0x61, 0x00, // LD V1, 0
+		0xA2, 0x0C, // LD I, 0x20C
+		0xD0, 0x11, // DRW V0, V1, 1
+		0x00, 0xFB, // SCR - scroll display right 4 pixels
+		0x80, // sprite data: a single lit pixel in the leftmost column
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeSCHIP)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < 6; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if !vm.Snapshot().Hires {
+		t.Fatal("VM did not switch to hi-res mode")
+	}
+
+	grid := vm.Snapshot().Planes[0]
+	if grid[4] != 1 {
+		t.Errorf("pixel at column 4 after scrolling right 4 = %d, want 1", grid[4])
+	}
+	if grid[0] != 0 {
+		t.Errorf("pixel at column 0 after scrolling right 4 = %d, want 0 (not left behind)", grid[0])
+	}
+}
+
+// TestBigFont covers Fx30 (LD HF, Vx), which must point I at the
+// SUPER-CHIP large font glyph for Vx, immediately after the small font.
+func TestBigFont(t *testing.T) {
+	rom := []byte{
+		0x60, 0x00, // LD V0, 0
+		0xF0, 0x30, // LD HF, V0
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeSCHIP)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < 2; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	const bigFontOffset = 80 // immediately after the 16 5-byte small-font glyphs
+	if got := vm.I(); got != bigFontOffset {
+		t.Errorf("I = %#x, want %#x (start of the big font)", got, bigFontOffset)
+	}
+}
+
+// TestRPLFlags covers Fx75/Fx85, which save and restore V0..Vx through the
+// SUPER-CHIP RPL user flags.
+func TestRPLFlags(t *testing.T) {
+	rom := []byte{
+		0x60, 0x01, // LD V0, 1
+		0x61, 0x02, // LD V1, 2
+		0xF1, 0x75, // LD R, V1 - save V0..V1 to RPL flags
+		0x60, 0x00, // LD V0, 0
+		0x61, 0x00, // LD V1, 0
+		0xF1, 0x85, // LD V1, R - restore V0..V1 from RPL flags
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeSCHIP)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	loadProgram(t, vm, rom)
+
+	for i := 0; i < len(rom)/2; i++ {
+		if err := vm.Step(); err != nil {
+			t.Fatalf("Step: %v", err)
+		}
+	}
+
+	if got := vm.V(0); got != 1 {
+		t.Errorf("V0 = %d, want 1", got)
+	}
+	if got := vm.V(1); got != 2 {
+		t.Errorf("V1 = %d, want 2", got)
+	}
+}
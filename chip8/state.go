@@ -0,0 +1,229 @@
+package chip8
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// stateMagic and stateVersion identify chopper save-states written by
+// State.MarshalBinary, so State.UnmarshalBinary can reject files that aren't one
+// or that were written by an incompatible future version.
+const (
+	stateMagic   = "CH8S"
+	stateVersion = 1
+)
+
+// State is a point-in-time capture of a VM's full internal state: mode,
+// quirks, memory, registers, stack, timers, display planes, key mask, and
+// the RNG seed. It's produced by Snapshot and consumed by Restore, and is
+// serializable so it can be written to disk as a save-state.
+type State struct {
+	Mode        Mode
+	Quirks      Quirks
+	Memory      []uint8
+	V           [16]uint8
+	I           uint16
+	DelayTimer  uint8
+	SoundTimer  uint8
+	PC          uint16
+	SP          uint8
+	Stack       [16]uint16
+	RPL         [16]uint8
+	AudioBuffer [16]uint8
+	Pitch       uint8
+	Key         uint16
+	Hires       bool
+	Plane       uint8
+	Planes      [2][]uint8
+	Seed        int64
+}
+
+// Snapshot captures the VM's complete current state.
+func (vm *VM) Snapshot() State {
+	s := State{
+		Mode:        vm.mode,
+		Quirks:      vm.quirks,
+		V:           vm.regV,
+		I:           vm.regI,
+		DelayTimer:  vm.delayTimer,
+		SoundTimer:  vm.soundTimer,
+		PC:          vm.pc,
+		SP:          vm.sp,
+		Stack:       vm.stack,
+		RPL:         vm.rpl,
+		AudioBuffer: vm.audioBuffer,
+		Pitch:       vm.pitch,
+		Key:         vm.key,
+		Hires:       vm.hires,
+		Plane:       vm.plane,
+		Seed:        vm.seed,
+	}
+	s.Memory = make([]uint8, len(vm.memory))
+	copy(s.Memory, vm.memory)
+	for p := range vm.planes {
+		s.Planes[p] = make([]uint8, len(vm.planes[p]))
+		copy(s.Planes[p], vm.planes[p])
+	}
+	return s
+}
+
+// Restore replaces the VM's current state with a previously captured
+// State, re-seeding the RNG so replays of a recorded run from this point
+// stay deterministic. A redraw is forced on the next Run iteration.
+func (vm *VM) Restore(s State) {
+	vm.mode = s.Mode
+	vm.quirks = s.Quirks
+	vm.regV = s.V
+	vm.regI = s.I
+	vm.delayTimer = s.DelayTimer
+	vm.soundTimer = s.SoundTimer
+	vm.pc = s.PC
+	vm.sp = s.SP
+	vm.stack = s.Stack
+	vm.rpl = s.RPL
+	vm.audioBuffer = s.AudioBuffer
+	vm.pitch = s.Pitch
+	vm.key = s.Key
+	vm.hires = s.Hires
+	vm.plane = s.Plane
+	vm.seed = s.Seed
+	vm.rng = newRNG(s.Seed)
+
+	vm.memory = make([]uint8, len(s.Memory))
+	copy(vm.memory, s.Memory)
+	for p := range s.Planes {
+		vm.planes[p] = make([]uint8, len(s.Planes[p]))
+		copy(vm.planes[p], s.Planes[p])
+	}
+	vm.drawFlag = true
+}
+
+// SaveJSON writes s to path as JSON.
+func (s State) SaveJSON(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("chip8: error encoding state: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("chip8: error writing state: %v", err)
+	}
+	return nil
+}
+
+// LoadStateJSON reads a State previously written by SaveJSON.
+func LoadStateJSON(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, fmt.Errorf("chip8: error reading state: %v", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("chip8: error decoding state: %v", err)
+	}
+	return s, nil
+}
+
+// MarshalBinary encodes s as a versioned, gzip-compressed save-state: a
+// 4-byte "CH8S" magic, a version byte, then a gob-encoded, gzipped
+// payload. This is chopper's canonical on-disk save-state format.
+func (s State) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(stateMagic)
+	buf.WriteByte(stateVersion)
+
+	gz := gzip.NewWriter(&buf)
+	if err := gob.NewEncoder(gz).Encode(s); err != nil {
+		return nil, fmt.Errorf("chip8: error encoding state: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("chip8: error encoding state: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a save-state written by MarshalBinary.
+func (s *State) UnmarshalBinary(data []byte) error {
+	if len(data) < 5 || string(data[:4]) != stateMagic {
+		return fmt.Errorf("chip8: not a chopper save-state")
+	}
+	if version := data[4]; version != stateVersion {
+		return fmt.Errorf("chip8: unsupported save-state version %d", version)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data[5:]))
+	if err != nil {
+		return fmt.Errorf("chip8: error reading state: %v", err)
+	}
+	defer gz.Close()
+	if err := gob.NewDecoder(gz).Decode(s); err != nil {
+		return fmt.Errorf("chip8: error decoding state: %v", err)
+	}
+	return nil
+}
+
+// SaveGob writes s to path using MarshalBinary's versioned, gzip-compressed
+// format, which is far more compact than JSON for repeated save-states.
+func (s State) SaveGob(path string) error {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("chip8: error writing state: %v", err)
+	}
+	return nil
+}
+
+// LoadStateGob reads a State previously written by SaveGob.
+func LoadStateGob(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, fmt.Errorf("chip8: error reading state: %v", err)
+	}
+	if err := s.UnmarshalBinary(data); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Rewind is a fixed-size ring buffer of recent States, letting a frontend
+// step backwards through a VM's recent history (e.g. to bind an "undo"
+// hotkey). It is not safe for concurrent use.
+type Rewind struct {
+	states []State
+	next   int
+	count  int
+}
+
+// NewRewind returns a Rewind holding up to capacity States.
+func NewRewind(capacity int) *Rewind {
+	return &Rewind{states: make([]State, capacity)}
+}
+
+// Push records a new State, discarding the oldest one once the buffer is
+// full.
+func (r *Rewind) Push(s State) {
+	if len(r.states) == 0 {
+		return
+	}
+	r.states[r.next] = s
+	r.next = (r.next + 1) % len(r.states)
+	if r.count < len(r.states) {
+		r.count++
+	}
+}
+
+// StepBack pops and returns the most recently pushed State. It returns
+// false if the buffer is empty.
+func (r *Rewind) StepBack() (State, bool) {
+	if r.count == 0 {
+		return State{}, false
+	}
+	r.next = (r.next - 1 + len(r.states)) % len(r.states)
+	r.count--
+	return r.states[r.next], true
+}
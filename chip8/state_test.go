@@ -0,0 +1,55 @@
+package chip8_test
+
+import (
+	"testing"
+
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/chip8/chip8test"
+)
+
+// TestSnapshotRestoreRoundTrip covers Snapshot/Restore round-tripping a
+// VM's registers, I and PC: restoring an earlier snapshot must undo any
+// execution that happened after it was taken.
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	rom := []byte{
+		0x60, 0x05, // LD V0, 5
+		0xA2, 0x10, // LD I, 0x210
+		0x61, 0x09, // LD V1, 9
+	}
+	frontend := chip8test.NewFrontend()
+	vm, err := chip8.NewVM(frontend, chip8.ModeCHIP8)
+	if err != nil {
+		t.Fatalf("NewVM: %v", err)
+	}
+	loadProgram(t, vm, rom)
+
+	if err := vm.Step(); err != nil { // LD V0, 5
+		t.Fatalf("Step: %v", err)
+	}
+	if err := vm.Step(); err != nil { // LD I, 0x210
+		t.Fatalf("Step: %v", err)
+	}
+	snapshot := vm.Snapshot()
+
+	if err := vm.Step(); err != nil { // LD V1, 9
+		t.Fatalf("Step: %v", err)
+	}
+	if got := vm.V(1); got != 9 {
+		t.Fatalf("V1 = %d, want 9 before restoring", got)
+	}
+
+	vm.Restore(snapshot)
+
+	if got := vm.V(0); got != 5 {
+		t.Errorf("V0 = %d, want 5", got)
+	}
+	if got := vm.I(); got != 0x210 {
+		t.Errorf("I = %#x, want 0x210", got)
+	}
+	if got, want := vm.PC(), uint16(0x204); got != want {
+		t.Errorf("PC = %#x, want %#x", got, want)
+	}
+	if got := vm.V(1); got != 0 {
+		t.Errorf("V1 = %d, want 0 (execution after the snapshot must be undone)", got)
+	}
+}
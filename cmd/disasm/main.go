@@ -0,0 +1,26 @@
+// Command chopper-disasm prints a static disassembly of a CHIP-8 ROM.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mnafees/chopper/chip8/debug"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: chopper-disasm <CHIP-8 program>")
+		os.Exit(1)
+	}
+
+	rom, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, ins := range debug.Disassemble(rom) {
+		fmt.Printf("%04X: %04X  %s\n", ins.Addr, ins.Opcode, ins.Mnemonic)
+	}
+}
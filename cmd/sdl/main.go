@@ -1,32 +1,143 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/mnafees/chopper/internal"
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/chip8/debug"
+	"github.com/mnafees/chopper/internal/cliconfig"
 	"github.com/mnafees/chopper/pkg/sdl"
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: chopper <CHIP-8 program>")
+	common := cliconfig.RegisterFlags()
+	beepFreq := flag.Int("beep-freq", 440, "Frequency, in Hz, of the beep tone")
+	rewindFrames := flag.Int("rewind-frames", 600, "Number of drawn frames kept for the Backspace rewind hotkey")
+	debugMode := flag.Bool("debug", false, "Single-step with a disassembly window, instead of free-running")
+	flag.Parse()
+	romPath := cliconfig.ROMPath("Usage: chopper [-mode chip8|schip|xochip] [-quirks PROFILE] <CHIP-8 program>")
+
+	mode, err := chip8.ParseMode(*common.Mode)
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	vm, err := internal.NewC8VM()
-	if err != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frontend := sdl.NewFrontend(cancel)
+	frontend.SetBeepFrequency(*beepFreq)
+	if err := frontend.SetupWindow("Chopper | CHIP-8 Emulator"); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	err = vm.LoadProgram(os.Args[1])
+	defer frontend.Destroy()
+
+	vm, err := chip8.NewVM(frontend, mode)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	vm.SetClockSpeed(*common.IPS)
+	frontend.BindVM(vm, *rewindFrames)
+	if err := vm.LoadProgram(romPath); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if quirks, ok, err := cliconfig.ResolveQuirks(romPath, *common.Quirks, *common.QuirksConfig); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	} else if ok {
+		vm.SetQuirks(quirks)
+	}
+
+	if *debugMode {
+		rom, err := os.ReadFile(romPath)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err := runDebugLoop(ctx, vm, rom); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := vm.Run(ctx); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// runDebugLoop single-steps vm, printing a disassembly window around the
+// current PC and the register file before each step, and waiting on
+// stdin between steps: Enter single-steps, "c" free-runs until ctx is
+// cancelled or a breakpoint is hit, and "q" quits.
+func runDebugLoop(ctx context.Context, vm *chip8.VM, rom []byte) error {
+	instructions := debug.Disassemble(rom)
+	byAddr := make(map[uint16]debug.Instruction, len(instructions))
+	for _, ins := range instructions {
+		byAddr[ins.Addr] = ins
+	}
+
+	stdin := bufio.NewScanner(os.Stdin)
+	running := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
 
-	io := sdl.NewIO(vm)
-	defer io.Destroy()
-	io.SetupWindow("Chopper | CHIP-8 Emulator")
-	io.Loop()
+		printWindow(byAddr, vm)
+
+		if running && !vm.AtBreakpoint() {
+			if err := vm.Step(); err != nil {
+				return err
+			}
+			continue
+		}
+		running = false
+
+		fmt.Print("(chopper-debug) ")
+		if !stdin.Scan() {
+			return nil
+		}
+		switch strings.TrimSpace(stdin.Text()) {
+		case "q":
+			return nil
+		case "c":
+			running = true
+		default:
+			if err := vm.Step(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func printWindow(byAddr map[uint16]debug.Instruction, vm *chip8.VM) {
+	pc := vm.PC()
+	for addr := pc - 4; addr <= pc+4; addr += 2 {
+		marker := "  "
+		if addr == pc {
+			marker = "->"
+		}
+		if ins, ok := byAddr[addr]; ok {
+			fmt.Printf("%s %04X: %s\n", marker, addr, ins.Mnemonic)
+		}
+	}
+	fmt.Printf("I=%04X SP=%d DT=%d ST=%d\n", vm.I(), vm.SP(), vm.DelayTimer(), vm.SoundTimer())
+	for i := 0; i < 16; i++ {
+		fmt.Printf("V%X=%02X ", i, vm.V(uint8(i)))
+	}
+	fmt.Println()
 }
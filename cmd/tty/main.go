@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mnafees/chopper/chip8"
+	"github.com/mnafees/chopper/internal/cliconfig"
+	"github.com/mnafees/chopper/pkg/tcell"
+)
+
+func main() {
+	common := cliconfig.RegisterFlags()
+	flag.Parse()
+	romPath := cliconfig.ROMPath("Usage: chopper-tty [-mode chip8|schip|xochip] [-quirks PROFILE] <CHIP-8 program>")
+
+	mode, err := chip8.ParseMode(*common.Mode)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frontend := tcell.NewFrontend(cancel)
+	if err := frontend.Init(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer frontend.Destroy()
+
+	vm, err := chip8.NewVM(frontend, mode)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	vm.SetClockSpeed(*common.IPS)
+	if err := vm.LoadProgram(romPath); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if quirks, ok, err := cliconfig.ResolveQuirks(romPath, *common.Quirks, *common.QuirksConfig); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	} else if ok {
+		vm.SetQuirks(quirks)
+	}
+
+	if err := vm.Run(ctx); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,69 @@
+// Package cliconfig holds the -mode/-quirks/-quirks-config/-ips flags and
+// the quirks-resolution logic shared by chopper's cmd/sdl, cmd/tty and
+// cmd/ansi binaries, so each one doesn't reimplement the same flags and
+// resolveQuirks function.
+package cliconfig
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mnafees/chopper/chip8"
+)
+
+// Flags holds the common flags registered by RegisterFlags. Read their
+// values only after flag.Parse().
+type Flags struct {
+	Mode         *string
+	Quirks       *string
+	QuirksConfig *string
+	IPS          *int
+}
+
+// RegisterFlags registers the flags common to every chopper frontend
+// binary against flag.CommandLine.
+func RegisterFlags() *Flags {
+	return &Flags{
+		Mode:         flag.String("mode", "chip8", "CHIP-8 dialect to run: chip8, schip or xochip"),
+		Quirks:       flag.String("quirks", "", "Quirks profile to use: COSMAC_VIP, SCHIP or XO_CHIP (defaults to one matching -mode)"),
+		QuirksConfig: flag.String("quirks-config", "", "Path to a JSON file mapping ROM SHA-1 hashes to quirks profile names"),
+		IPS:          flag.Int("ips", chip8.DefaultClockSpeed, "Instructions executed per second"),
+	}
+}
+
+// ROMPath returns flag.Arg(0), printing usage and exiting if exactly one
+// positional argument wasn't given.
+func ROMPath(usage string) string {
+	if flag.NArg() != 1 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	return flag.Arg(0)
+}
+
+// ResolveQuirks picks the Quirks profile to use, preferring (in order) an
+// explicit -quirks flag, a per-ROM entry in -quirks-config, and otherwise
+// the VM's mode-based default.
+func ResolveQuirks(romPath, quirksName, quirksConfigPath string) (chip8.Quirks, bool, error) {
+	if quirksName != "" {
+		q, ok := chip8.Profiles[quirksName]
+		if !ok {
+			return chip8.Quirks{}, false, fmt.Errorf("unknown quirks profile %q", quirksName)
+		}
+		return q, true, nil
+	}
+	if quirksConfigPath == "" {
+		return chip8.Quirks{}, false, nil
+	}
+	cfg, err := chip8.LoadROMQuirksConfig(quirksConfigPath)
+	if err != nil {
+		return chip8.Quirks{}, false, err
+	}
+	rom, err := os.ReadFile(romPath)
+	if err != nil {
+		return chip8.Quirks{}, false, fmt.Errorf("chip8: error reading program: %v", err)
+	}
+	q, ok := cfg.Lookup(rom)
+	return q, ok, nil
+}
@@ -0,0 +1,235 @@
+//go:build !windows
+
+// Package ansi provides a chip8.Frontend that renders to any ANSI
+// terminal using Unicode half-blocks, so chopper can run headless over
+// SSH with no SDL or tcell dependency. Each terminal cell encodes two
+// vertically stacked CHIP-8 pixels via the "▀" glyph's foreground and
+// background colors.
+package ansi
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/term"
+)
+
+const halfBlock = "▀"
+
+// keyHoldDuration is how long a key stays "pressed" after a raw stdin
+// byte is read for it, since raw terminal input has no key-release event
+// to clear it on.
+const keyHoldDuration = 150 * time.Millisecond
+
+// Frontend is a chip8.Frontend backed by raw ANSI escape codes.
+type Frontend struct {
+	out    io.Writer
+	cancel func()
+	keymap map[rune]int8
+
+	mu      sync.Mutex
+	key     uint16
+	beeping bool
+
+	oldState *term.State
+	resized  chan struct{}
+}
+
+// NewFrontend returns a new ansi-backed Frontend writing to w (os.Stdout
+// if nil). cancel is called when the user asks to quit (Ctrl-C).
+func NewFrontend(w io.Writer, cancel func()) *Frontend {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &Frontend{out: w, cancel: cancel, keymap: defaultKeymap(), resized: make(chan struct{}, 1)}
+}
+
+// SetKeymap replaces the rune-to-keypad mapping used to translate stdin
+// bytes into CHIP-8 keys.
+func (f *Frontend) SetKeymap(m map[rune]int8) {
+	f.keymap = m
+}
+
+// Init switches stdin to raw mode, hides the cursor, and starts watching
+// for terminal resizes (SIGWINCH) and keyboard input.
+func (f *Frontend) Init() error {
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("ansi: error entering raw mode: %v", err)
+	}
+	f.oldState = state
+	fmt.Fprint(f.out, "\x1b[?25l")
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	go func() {
+		for range winch {
+			select {
+			case f.resized <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	go f.readStdin()
+	return nil
+}
+
+// Destroy restores the terminal to its previous state.
+func (f *Frontend) Destroy() {
+	fmt.Fprint(f.out, "\x1b[?25h")
+	if f.oldState != nil {
+		term.Restore(int(os.Stdin.Fd()), f.oldState)
+	}
+}
+
+// winsize mirrors struct winsize from <sys/ioctl.h>, for TIOCGWINSZ.
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// TerminalSize returns the current terminal size in columns and rows.
+func TerminalSize() (cols, rows int, err error) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0, 0, errno
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// Draw renders pixels to the terminal as half-blocks, nearest-neighbour
+// scaling to fit whatever size TerminalSize reports. It homes the cursor
+// rather than clearing the screen between frames, to avoid flicker.
+func (f *Frontend) Draw(pixels [][]byte) {
+	w := len(pixels)
+	if w == 0 {
+		return
+	}
+	h := len(pixels[0])
+
+	cols, termRows, err := TerminalSize()
+	if err != nil || cols <= 0 || termRows <= 0 {
+		cols, termRows = w, h/2
+	}
+	rows := termRows * 2 // two CHIP-8 rows per terminal row of half-blocks
+	if cols > w {
+		cols = w
+	}
+	if rows > h {
+		rows = h
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for ty := 0; ty < rows; ty += 2 {
+		for tx := 0; tx < cols; tx++ {
+			sx := tx * w / cols
+			top := pixels[sx][ty*h/rows] != 0
+			bot := pixels[sx][(ty+1)*h/rows] != 0
+			b.WriteString(sgr(top, bot))
+			b.WriteString(halfBlock)
+		}
+		b.WriteString("\x1b[0m\r\n")
+	}
+	io.WriteString(f.out, b.String())
+}
+
+// sgr returns the escape sequence setting the foreground and background
+// colors of a half-block cell whose top and bottom CHIP-8 pixels are lit.
+func sgr(top, bot bool) string {
+	fg, bg := 30, 40 // black on black
+	if top {
+		fg = 37 // white foreground draws the top pixel
+	}
+	if bot {
+		bg = 47 // white background draws the bottom pixel
+	}
+	return fmt.Sprintf("\x1b[%d;%dm", fg, bg)
+}
+
+// Beep writes a terminal bell on the rising edge of the VM's tone, since
+// terminals have no portable way to sustain one.
+func (f *Frontend) Beep(on bool) {
+	f.mu.Lock()
+	rising := on && !f.beeping
+	f.beeping = on
+	f.mu.Unlock()
+	if rising {
+		fmt.Fprint(f.out, "\a")
+	}
+}
+
+// PollKeys returns the current keypad state as tracked by readStdin.
+func (f *Frontend) PollKeys() uint16 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.key
+}
+
+// WaitKey blocks until a keypad key is pressed and returns it.
+func (f *Frontend) WaitKey() uint8 {
+	for {
+		f.mu.Lock()
+		key := f.key
+		f.mu.Unlock()
+		for i := uint8(0); i < 16; i++ {
+			if key&(1<<i) != 0 {
+				return i
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readStdin translates raw stdin bytes into keypad presses using keymap,
+// clearing each one again after keyHoldDuration since raw mode delivers no
+// key-release event.
+func (f *Frontend) readStdin() {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil || n == 0 {
+			return
+		}
+		r := rune(buf[0])
+		if r == 0x03 { // Ctrl-C
+			if f.cancel != nil {
+				f.cancel()
+			}
+			return
+		}
+		code, ok := f.keymap[r]
+		if !ok {
+			continue
+		}
+		f.mu.Lock()
+		f.key |= 1 << uint8(code)
+		f.mu.Unlock()
+
+		go func(code int8) {
+			time.Sleep(keyHoldDuration)
+			f.mu.Lock()
+			f.key &^= 1 << uint8(code)
+			f.mu.Unlock()
+		}(code)
+	}
+}
+
+// defaultKeymap maps a QWERTY keyboard to the CHIP-8 keypad, matching the
+// layout used by the SDL and tcell frontends.
+func defaultKeymap() map[rune]int8 {
+	return map[rune]int8{
+		'1': 0x1, '2': 0x2, '3': 0x3, '4': 0xC,
+		'q': 0x4, 'w': 0x5, 'e': 0x6, 'r': 0xD,
+		'a': 0x7, 's': 0x8, 'd': 0x9, 'f': 0xE,
+		'z': 0xA, 'x': 0x0, 'c': 0xB, 'v': 0xF,
+	}
+}
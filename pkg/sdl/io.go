@@ -2,133 +2,277 @@ package sdl
 
 import (
 	"fmt"
-	"os"
-	"time"
+	"math"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 
-	"github.com/mnafees/chopper/internal"
+	"github.com/mnafees/chopper/chip8"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 const (
+	// pixelSize is the scale used at CHIP-8's native 64x32 resolution.
+	// SUPER-CHIP/XO-CHIP's 128x64 hi-res mode is drawn into the same
+	// window, so it ends up scaled down by half.
 	pixelSize = 20
 
 	screenColor = 0x1A237E
 	spriteColor = 0x9FA8DA
+
+	audioSampleRate      = 44100
+	defaultBeepFrequency = 440
+	audioAmplitude       = 6000
 )
 
-// IO is the input/output abstraction layer for the VM
-type IO struct {
+// Frontend is a chip8.Frontend backed by an SDL window.
+type Frontend struct {
 	window  *sdl.Window
 	surface *sdl.Surface
 
-	vm *internal.C8VM
+	key    uint16
+	cancel func()
+
+	audioDevice   sdl.AudioDeviceID
+	beepFrequency int
+	beeping       int32 // Set atomically; read from the audio callback's own goroutine
+	sampleIndex   int
+
+	audioMu   sync.Mutex // Guards pattern/pitch/patternOn, read from the audio callback's own goroutine
+	pattern   [16]uint8
+	pitch     uint8
+	patternOn bool
+
+	vm        *chip8.VM
+	rewind    *chip8.Rewind
+	quickSave chip8.State
+	hasQuick  bool
 }
 
-// NewIO returns a new I/O instance for the SDL frontend
-func NewIO(vm *internal.C8VM) *IO {
-	return &IO{
-		vm: vm,
-	}
+// NewFrontend returns a new SDL-backed Frontend. cancel is called when the
+// user closes the window, so the caller can stop the VM's Run loop.
+func NewFrontend(cancel func()) *Frontend {
+	return &Frontend{cancel: cancel, beepFrequency: defaultBeepFrequency}
+}
+
+// SetBeepFrequency sets the frequency, in Hz, of the square wave played
+// while the VM's sound timer is active. It must be called before
+// SetupWindow opens the audio device.
+func (f *Frontend) SetBeepFrequency(hz int) {
+	f.beepFrequency = hz
+}
+
+// BindVM lets the Frontend drive vm's save-state hotkeys: F5 quicksaves,
+// F9 quickloads, and Backspace steps back through a rewind buffer of the
+// last rewindFrames draws. Call it once, after constructing vm.
+func (f *Frontend) BindVM(vm *chip8.VM, rewindFrames int) {
+	f.vm = vm
+	f.rewind = chip8.NewRewind(rewindFrames)
 }
 
 // SetupWindow initialises and sets up the main SDL window
-func (io *IO) SetupWindow(title string) {
+func (f *Frontend) SetupWindow(title string) error {
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
-		fmt.Printf("Error initialising SDL: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("error initialising SDL: %v", err)
 	}
 
 	window, err := sdl.CreateWindow(title, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
-		internal.ScreenWidth*pixelSize, internal.ScreenHeight*pixelSize, sdl.WINDOW_SHOWN)
+		chip8.ScreenWidth*pixelSize, chip8.ScreenHeight*pixelSize, sdl.WINDOW_SHOWN)
 	if err != nil {
-		fmt.Printf("Error creating window: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("error creating window: %v", err)
 	}
-	io.window = window
-	io.surface, err = window.GetSurface()
+	f.window = window
+	f.surface, err = window.GetSurface()
 	if err != nil {
-		fmt.Printf("Error getting window surface: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error getting window surface: %v", err)
+	}
+	f.surface.FillRect(nil, screenColor)
+
+	want := &sdl.AudioSpec{
+		Freq:     audioSampleRate,
+		Format:   sdl.AUDIO_S16SYS,
+		Channels: 1,
+		Samples:  1024,
+		Callback: sdl.AudioCallback(f.audioCallback),
 	}
-	io.surface.FillRect(nil, screenColor)
+	device, err := sdl.OpenAudioDevice("", false, want, nil, 0)
+	if err != nil {
+		return fmt.Errorf("error opening audio device: %v", err)
+	}
+	f.audioDevice = device
+	sdl.PauseAudioDevice(f.audioDevice, false)
+	return nil
 }
 
 // Destroy should be called before quitting the application
-func (io *IO) Destroy() {
-	io.window.Destroy()
+func (f *Frontend) Destroy() {
+	sdl.CloseAudioDevice(f.audioDevice)
+	f.window.Destroy()
 	sdl.Quit()
 }
 
-// Loop is the main application loop
-func (io *IO) Loop() {
-	running := true
-	for running {
-		err := io.vm.ReadNextInstruction()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
+// Draw renders the current display to the SDL window, scaling so that the
+// display always fills the window regardless of whether the VM is in
+// low-res or SUPER-CHIP/XO-CHIP hi-res mode.
+func (f *Frontend) Draw(pixels [][]byte) {
+	width := int32(len(pixels))
+	if width == 0 {
+		return
+	}
+	height := int32(len(pixels[0]))
+	scale := int32(chip8.ScreenWidth*pixelSize) / width
 
-		if io.vm.IsClearFlagSet() {
-			io.clearScreen()
+	f.surface.FillRect(nil, screenColor)
+	for w := int32(0); w < width; w++ {
+		for h := int32(0); h < height; h++ {
+			if pixels[w][h] != 0 {
+				rect := &sdl.Rect{w * scale, h * scale, scale, scale}
+				f.surface.FillRect(rect, spriteColor)
+			}
 		}
+	}
+	f.window.UpdateSurface()
 
-		if io.vm.IsDrawFlagSet() {
-			io.draw()
-		}
+	if f.rewind != nil {
+		f.rewind.Push(f.vm.Snapshot())
+	}
+}
 
-		if float64(time.Since(io.vm.PrevTime()).Milliseconds()) >= internal.TimerFrequency {
-			if io.vm.DelayTimer() > 0 {
-				io.vm.DecrementDelayTimer()
-			}
-			if io.vm.SoundTimer() > 0 {
-				io.vm.DecrementSoundTimer()
+// Beep turns the square-wave tone played through the audio device on or
+// off.
+func (f *Frontend) Beep(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&f.beeping, v)
+}
+
+// BeepPattern implements chip8.PatternBeeper, streaming XO-CHIP's 16-byte
+// audio pattern buffer as a 128-bit waveform at the rate pitch selects,
+// taking over from the plain square-wave Beep while on is true.
+func (f *Frontend) BeepPattern(buf [16]uint8, pitch uint8, on bool) {
+	f.audioMu.Lock()
+	f.pattern = buf
+	f.pitch = pitch
+	f.patternOn = on
+	f.audioMu.Unlock()
+}
+
+// audioCallback fills stream with either the XO-CHIP audio pattern (while
+// BeepPattern last turned it on) or a plain square wave at beepFrequency
+// (while Beep last turned it on), or silence otherwise. It runs on SDL's
+// own audio thread.
+func (f *Frontend) audioCallback(userdata unsafe.Pointer, stream *byte, length int) {
+	samples := unsafe.Slice((*int16)(unsafe.Pointer(stream)), length/2)
+
+	f.audioMu.Lock()
+	pattern, pitch, patternOn := f.pattern, f.pitch, f.patternOn
+	f.audioMu.Unlock()
+
+	if patternOn {
+		rate := 4000 * math.Pow(2, (float64(pitch)-64)/48)
+		samplesPerBit := int(audioSampleRate / rate)
+		if samplesPerBit < 1 {
+			samplesPerBit = 1
+		}
+		for i := range samples {
+			bit := (f.sampleIndex / samplesPerBit) % 128
+			if pattern[bit/8]&(0x80>>uint(bit%8)) != 0 {
+				samples[i] = audioAmplitude
+			} else {
+				samples[i] = -audioAmplitude
 			}
-			io.vm.UpdatePrevTime()
+			f.sampleIndex++
 		}
+		return
+	}
 
-		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-			switch t := event.(type) {
-			case *sdl.KeyboardEvent:
-				keycode := t.Keysym.Scancode
-				switch t.GetType() {
-				case sdl.KEYDOWN:
-					io.setKeymask(keycode)
-					break
-				case sdl.KEYUP:
-					io.unsetKeymask(keycode)
-				}
-				break
-			case *sdl.QuitEvent:
-				running = false
+	if atomic.LoadInt32(&f.beeping) == 0 {
+		for i := range samples {
+			samples[i] = 0
+		}
+		return
+	}
+	halfPeriod := audioSampleRate / f.beepFrequency / 2
+	for i := range samples {
+		if (f.sampleIndex/halfPeriod)%2 == 0 {
+			samples[i] = audioAmplitude
+		} else {
+			samples[i] = -audioAmplitude
+		}
+		f.sampleIndex++
+	}
+}
+
+// PollKeys drains the SDL event queue, updating the key mask and
+// triggering cancel if the window was closed.
+func (f *Frontend) PollKeys() uint16 {
+	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+		switch t := event.(type) {
+		case *sdl.KeyboardEvent:
+			if t.GetType() == sdl.KEYDOWN {
+				f.handleHotkey(t.Keysym.Scancode)
+			}
+			code := keymap(t.Keysym.Scancode)
+			if code == -1 {
 				break
 			}
+			switch t.GetType() {
+			case sdl.KEYDOWN:
+				f.key |= (1 << uint8(code))
+			case sdl.KEYUP:
+				f.key &^= (1 << uint8(code))
+			}
+		case *sdl.QuitEvent:
+			if f.cancel != nil {
+				f.cancel()
+			}
 		}
 	}
+	return f.key
 }
 
-// Clear the current appl [internal.ScreenWidth][internal.ScreenHeight]byteication screen
-func (io *IO) clearScreen() {
-	io.vm.NullifyPixels()
-	io.surface.FillRect(nil, screenColor)
-	io.window.UpdateSurface()
-	io.vm.UnsetClearFlag()
+// handleHotkey services the save-state hotkeys bound by BindVM, if any.
+func (f *Frontend) handleHotkey(code sdl.Scancode) {
+	if f.vm == nil {
+		return
+	}
+	switch code {
+	case sdl.SCANCODE_F5: // quicksave
+		f.quickSave = f.vm.Snapshot()
+		f.hasQuick = true
+	case sdl.SCANCODE_F9: // quickload
+		if f.hasQuick {
+			f.vm.Restore(f.quickSave)
+		}
+	case sdl.SCANCODE_BACKSPACE: // step back
+		if s, ok := f.rewind.StepBack(); ok {
+			f.vm.Restore(s)
+		}
+	}
 }
 
-// Draws the current sprite configuration on screen
-func (io *IO) draw() {
-	io.surface.FillRect(nil, screenColor)
-	pixels := io.vm.Pixels()
-	for w := int32(0); w < internal.ScreenWidth; w++ {
-		for h := int32(0); h < internal.ScreenHeight; h++ {
-			if pixels[w][h] == 1 {
-				rect := &sdl.Rect{w * pixelSize, h * pixelSize, pixelSize, pixelSize}
-				io.surface.FillRect(rect, spriteColor)
+// WaitKey blocks until a keypad key is pressed and returns it.
+func (f *Frontend) WaitKey() uint8 {
+	for {
+		event := sdl.WaitEvent()
+		switch t := event.(type) {
+		case *sdl.KeyboardEvent:
+			if t.GetType() != sdl.KEYDOWN {
+				continue
+			}
+			code := keymap(t.Keysym.Scancode)
+			if code != -1 {
+				return uint8(code)
+			}
+		case *sdl.QuitEvent:
+			if f.cancel != nil {
+				f.cancel()
 			}
+			return 0
 		}
 	}
-	io.window.UpdateSurface()
-	io.vm.UnsetDrawFlag()
 }
 
 // Maps keys from a QWERTY keyboard to the keypad used by CHIP-8
@@ -142,7 +286,7 @@ func (io *IO) draw() {
 // +--------+--------+--------+--------+
 // | Z -> A | X -> 0 | C -> B | V -> F |
 // +--------+--------+--------+--------+
-func (io *IO) keymap(code sdl.Scancode) int8 {
+func keymap(code sdl.Scancode) int8 {
 	switch code {
 	case sdl.SCANCODE_1:
 		return 0x1
@@ -180,17 +324,3 @@ func (io *IO) keymap(code sdl.Scancode) int8 {
 		return -1
 	}
 }
-
-func (io *IO) setKeymask(keycode sdl.Scancode) {
-	code := io.keymap(keycode)
-	if code != -1 {
-		io.vm.SetKeymask(uint8(code))
-	}
-}
-
-func (io *IO) unsetKeymask(keycode sdl.Scancode) {
-	code := io.keymap(keycode)
-	if code != -1 {
-		io.vm.UnsetKeymask(uint8(code))
-	}
-}
@@ -0,0 +1,177 @@
+// Package tcell provides a chip8.Frontend that renders to any ANSI
+// terminal via gdamore/tcell, so chopper can run without SDL.
+package tcell
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const onCell = ' '
+
+// keyHoldDuration is how long a key stays "pressed" after a tcell key
+// event is received, since terminal key events have no key-release event
+// to clear it on.
+const keyHoldDuration = 150 * time.Millisecond
+
+var (
+	onStyle  = tcell.StyleDefault.Background(tcell.ColorWhite)
+	offStyle = tcell.StyleDefault.Background(tcell.ColorBlack)
+)
+
+// Frontend is a chip8.Frontend backed by a tcell terminal screen. Each
+// CHIP-8 pixel is drawn as two terminal columns, keeping the 64x32 display
+// roughly square in most terminal fonts.
+type Frontend struct {
+	screen tcell.Screen
+	cancel func()
+
+	mu  sync.Mutex
+	key uint16
+}
+
+// NewFrontend returns a new terminal-backed Frontend. cancel is called
+// when the user asks to quit (Esc or Ctrl-C), so the caller can stop the
+// VM's Run loop.
+func NewFrontend(cancel func()) *Frontend {
+	return &Frontend{cancel: cancel}
+}
+
+// Init initialises the terminal screen.
+func (f *Frontend) Init() error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	screen.HideCursor()
+	f.screen = screen
+	return nil
+}
+
+// Destroy should be called before quitting the application
+func (f *Frontend) Destroy() {
+	f.screen.Fini()
+}
+
+// Draw renders the current display to the terminal, two columns per
+// pixel. It adapts to whatever resolution the VM is currently rendering
+// at, so it works for both CHIP-8's 64x32 and SUPER-CHIP/XO-CHIP's 128x64
+// hi-res mode.
+func (f *Frontend) Draw(pixels [][]byte) {
+	f.screen.Clear()
+	for w := 0; w < len(pixels); w++ {
+		for h := 0; h < len(pixels[w]); h++ {
+			style := offStyle
+			if pixels[w][h] != 0 {
+				style = onStyle
+			}
+			f.screen.SetContent(w*2, h, onCell, nil, style)
+			f.screen.SetContent(w*2+1, h, onCell, nil, style)
+		}
+	}
+	f.screen.Show()
+}
+
+// Beep is currently a no-op; terminals have no portable way to produce a
+// sustained tone.
+func (f *Frontend) Beep(on bool) {}
+
+// PollKeys drains pending terminal events, updating the key mask and
+// triggering cancel on Esc or Ctrl-C.
+func (f *Frontend) PollKeys() uint16 {
+	for f.screen.HasPendingEvent() {
+		f.handleEvent(f.screen.PollEvent())
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.key
+}
+
+// WaitKey blocks until a keypad key is pressed and returns it.
+func (f *Frontend) WaitKey() uint8 {
+	for {
+		ev := f.screen.PollEvent()
+		if key, ok := f.handleEvent(ev); ok {
+			return key
+		}
+	}
+}
+
+// handleEvent handles a single tcell event, setting the key mask bit for
+// keypad keys. tcell delivers no key-release event, so the bit is cleared
+// again after keyHoldDuration, the same way pkg/ansi does.
+func (f *Frontend) handleEvent(ev tcell.Event) (uint8, bool) {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		if ev.Key() == tcell.KeyEsc || ev.Key() == tcell.KeyCtrlC {
+			if f.cancel != nil {
+				f.cancel()
+			}
+			return 0, false
+		}
+		code := keymap(ev.Rune())
+		if code == -1 {
+			return 0, false
+		}
+		f.mu.Lock()
+		f.key |= 1 << uint8(code)
+		f.mu.Unlock()
+
+		go func(code int8) {
+			time.Sleep(keyHoldDuration)
+			f.mu.Lock()
+			f.key &^= 1 << uint8(code)
+			f.mu.Unlock()
+		}(code)
+		return uint8(code), true
+	case *tcell.EventResize:
+		f.screen.Sync()
+	}
+	return 0, false
+}
+
+// Maps keys from a QWERTY keyboard to the keypad used by CHIP-8, matching
+// the layout used by the SDL frontend.
+func keymap(r rune) int8 {
+	switch r {
+	case '1':
+		return 0x1
+	case '2':
+		return 0x2
+	case '3':
+		return 0x3
+	case '4':
+		return 0xC
+	case 'q':
+		return 0x4
+	case 'w':
+		return 0x5
+	case 'e':
+		return 0x6
+	case 'r':
+		return 0xD
+	case 'a':
+		return 0x7
+	case 's':
+		return 0x8
+	case 'd':
+		return 0x9
+	case 'f':
+		return 0xE
+	case 'z':
+		return 0xA
+	case 'x':
+		return 0x0
+	case 'c':
+		return 0xB
+	case 'v':
+		return 0xF
+	default:
+		return -1
+	}
+}